@@ -35,9 +35,7 @@ type AnalyticsDeps struct {
 }
 
 func NewAnalyticsHandler(deps *AnalyticsDeps) model.Analytics {
-	if deps.Logger == nil {
-		deps.Logger = logging.GetLogger()
-	}
+	deps.Logger = logging.OrGlobal(deps.Logger)
 	flushSize := 500
 	if deps.FlushAtSize > 0 {
 		flushSize = deps.FlushAtSize
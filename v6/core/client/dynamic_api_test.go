@@ -14,7 +14,7 @@ import (
 func TestDynamicAPIIsEnabled(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -32,7 +32,7 @@ func TestDynamicAPIIsEnabled(t *testing.T) {
 func TestDynamicAPIIsEnabledAfterSetup(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -45,7 +45,7 @@ func TestDynamicAPIIsEnabledAfterSetup(t *testing.T) {
 func TestDynamicAPIGetStringValue(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -63,7 +63,7 @@ func TestDynamicAPIGetStringValue(t *testing.T) {
 func TestDynamicAPIGetStringValueWithFlagDependency(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -81,7 +81,7 @@ func TestDynamicAPIGetStringValueWithFlagDependency(t *testing.T) {
 func TestDynamicAPIGetIntValue(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -99,7 +99,7 @@ func TestDynamicAPIGetIntValue(t *testing.T) {
 func TestDynamicAPIGetDoubleValue(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -117,7 +117,7 @@ func TestDynamicAPIGetDoubleValue(t *testing.T) {
 func TestDynamicAPIGetValueWithoutOptions(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -135,7 +135,7 @@ func TestDynamicAPIGetValueWithoutOptions(t *testing.T) {
 func TestDynamicAPIFlagMixUp(t *testing.T) {
 	parser := roxx.NewParser()
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 	flagSetter := entities.NewFlagSetter(flagRepo, parser, expRepo, nil)
 	dynamicAPI := client.NewDynamicAPI(flagRepo, &entitiesMockProvider{})
 
@@ -1,6 +1,8 @@
 package configuration
 
 import (
+	"reflect"
+	"sort"
 	"time"
 
 	"github.com/rollout/rox-go/v6/core/model"
@@ -10,6 +12,8 @@ type Configuration struct {
 	Experiments   []*model.ExperimentModel
 	TargetGroups  []*model.TargetGroupModel
 	SignatureDate time.Time
+	// ValidationErrors are the problems Parser.Parse found in the conditions, the configuration is applied regardless
+	ValidationErrors []*ValidationError
 }
 
 func NewConfiguration(experiments []*model.ExperimentModel, targetGroups []*model.TargetGroupModel, signatureDate time.Time) *Configuration {
@@ -19,3 +23,78 @@ func NewConfiguration(experiments []*model.ExperimentModel, targetGroups []*mode
 		SignatureDate: signatureDate,
 	}
 }
+
+// Changes compares the configuration with the previously applied one, a nil previous configuration is treated as empty
+func (c *Configuration) Changes(previous *Configuration) model.ConfigurationChanges {
+	var previousExperiments []*model.ExperimentModel
+	var previousTargetGroups []*model.TargetGroupModel
+	if previous != nil {
+		previousExperiments = previous.Experiments
+		previousTargetGroups = previous.TargetGroups
+	}
+
+	changes := model.ConfigurationChanges{}
+	previousFlags := experimentsByFlag(previousExperiments)
+	flags := experimentsByFlag(c.Experiments)
+	for flag, experiment := range flags {
+		previousExperiment, ok := previousFlags[flag]
+		if !ok {
+			changes.AddedFlags = append(changes.AddedFlags, flag)
+		} else if !experimentsEqual(previousExperiment, experiment) {
+			changes.ChangedFlags = append(changes.ChangedFlags, flag)
+		}
+	}
+	for flag := range previousFlags {
+		if _, ok := flags[flag]; !ok {
+			changes.RemovedFlags = append(changes.RemovedFlags, flag)
+		}
+	}
+
+	previousConditions := targetGroupConditions(previousTargetGroups)
+	conditions := targetGroupConditions(c.TargetGroups)
+	for id, condition := range conditions {
+		if previousCondition, ok := previousConditions[id]; !ok || previousCondition != condition {
+			changes.ChangedTargetGroups = append(changes.ChangedTargetGroups, id)
+		}
+	}
+	for id := range previousConditions {
+		if _, ok := conditions[id]; !ok {
+			changes.ChangedTargetGroups = append(changes.ChangedTargetGroups, id)
+		}
+	}
+
+	sort.Strings(changes.AddedFlags)
+	sort.Strings(changes.RemovedFlags)
+	sort.Strings(changes.ChangedFlags)
+	sort.Strings(changes.ChangedTargetGroups)
+	return changes
+}
+
+func experimentsByFlag(experiments []*model.ExperimentModel) map[string]*model.ExperimentModel {
+	result := make(map[string]*model.ExperimentModel)
+	for _, experiment := range experiments {
+		for _, flag := range experiment.Flags {
+			// the experiment repository resolves a flag to its first experiment
+			if _, ok := result[flag]; !ok {
+				result[flag] = experiment
+			}
+		}
+	}
+	return result
+}
+
+func experimentsEqual(e1, e2 *model.ExperimentModel) bool {
+	return e1.ID == e2.ID &&
+		e1.Name == e2.Name &&
+		e1.Condition == e2.Condition &&
+		e1.IsArchived == e2.IsArchived &&
+		reflect.DeepEqual(e1.Labels, e2.Labels)
+}
+
+func targetGroupConditions(targetGroups []*model.TargetGroupModel) map[string]string {
+	result := make(map[string]string)
+	for _, targetGroup := range targetGroups {
+		result[targetGroup.ID] = targetGroup.Condition
+	}
+	return result
+}
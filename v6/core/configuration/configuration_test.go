@@ -0,0 +1,132 @@
+package configuration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/rollout/rox-go/v6/core/entities"
+	"github.com/rollout/rox-go/v6/core/extensions"
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/repositories"
+	"github.com/rollout/rox-go/v6/core/roxx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigurationChangesFromNothingWillAddAllFlags(t *testing.T) {
+	config := configuration.NewConfiguration(
+		[]*model.ExperimentModel{model.NewExperimentModel("1", "exp1", "true", false, []string{"b", "a"}, nil)},
+		[]*model.TargetGroupModel{model.NewTargetGroupModel("tg1", "true")},
+		time.Now())
+
+	changes := config.Changes(nil)
+
+	assert.Equal(t, []string{"a", "b"}, changes.AddedFlags)
+	assert.Empty(t, changes.RemovedFlags)
+	assert.Empty(t, changes.ChangedFlags)
+	assert.Equal(t, []string{"tg1"}, changes.ChangedTargetGroups)
+}
+
+func TestConfigurationChangesWillListAddedRemovedAndChangedFlags(t *testing.T) {
+	previous := configuration.NewConfiguration(
+		[]*model.ExperimentModel{
+			model.NewExperimentModel("1", "exp1", "true", false, []string{"same", "changed"}, nil),
+			model.NewExperimentModel("2", "exp2", "false", false, []string{"removed"}, nil),
+		},
+		[]*model.TargetGroupModel{
+			model.NewTargetGroupModel("tg1", "true"),
+			model.NewTargetGroupModel("tg2", "true"),
+			model.NewTargetGroupModel("tg3", "true"),
+		},
+		time.Now())
+	config := configuration.NewConfiguration(
+		[]*model.ExperimentModel{
+			model.NewExperimentModel("1", "exp1", "true", false, []string{"same"}, nil),
+			model.NewExperimentModel("3", "exp3", "true", false, []string{"changed", "added"}, nil),
+		},
+		[]*model.TargetGroupModel{
+			model.NewTargetGroupModel("tg1", "true"),
+			model.NewTargetGroupModel("tg2", "false"),
+			model.NewTargetGroupModel("tg4", "true"),
+		},
+		time.Now())
+
+	changes := config.Changes(previous)
+
+	assert.Equal(t, []string{"added"}, changes.AddedFlags)
+	assert.Equal(t, []string{"removed"}, changes.RemovedFlags)
+	assert.Equal(t, []string{"changed"}, changes.ChangedFlags)
+	assert.Equal(t, []string{"tg2", "tg3", "tg4"}, changes.ChangedTargetGroups)
+	assert.False(t, changes.IsEmpty())
+}
+
+func TestConfigurationChangesWillBeEmptyForSameConfiguration(t *testing.T) {
+	experiments := []*model.ExperimentModel{model.NewExperimentModel("1", "exp1", "true", false, []string{"a"}, []string{"label"})}
+	targetGroups := []*model.TargetGroupModel{model.NewTargetGroupModel("tg1", "true")}
+	previous := configuration.NewConfiguration(experiments, targetGroups, time.Now())
+	config := configuration.NewConfiguration(
+		[]*model.ExperimentModel{model.NewExperimentModel("1", "exp1", "true", false, []string{"a"}, []string{"label"})},
+		[]*model.TargetGroupModel{model.NewTargetGroupModel("tg1", "true")},
+		time.Now())
+
+	assert.True(t, config.Changes(previous).IsEmpty())
+}
+
+func TestConfigurationValidateWillReportUnknownReferences(t *testing.T) {
+	config := configuration.NewConfiguration(
+		[]*model.ExperimentModel{
+			model.NewExperimentModel("1", "exp1", `and(isInTargetGroup("tg1"), isInTargetGroup("missing"))`, false, []string{"flag1"}, nil),
+			model.NewExperimentModel("2", "exp2", `eq(flagValue("flag1"), flagValue("unknown"))`, false, []string{"flag2"}, nil),
+		},
+		[]*model.TargetGroupModel{model.NewTargetGroupModel("tg1", `unknownOperator("x")`)},
+		time.Now())
+
+	validationErrors := config.Validate(newExpressionParser(), nil)
+
+	assert.Equal(t, 3, len(validationErrors))
+	assert.Equal(t, "1", validationErrors[0].ExperimentID)
+	assert.Equal(t, "isInTargetGroup", validationErrors[0].Err.Operator)
+	assert.Equal(t, "unknown target group missing", validationErrors[0].Err.Message)
+	assert.Equal(t, "2", validationErrors[1].ExperimentID)
+	assert.Equal(t, "flagValue", validationErrors[1].Err.Operator)
+	assert.Equal(t, "unknown flag unknown", validationErrors[1].Err.Message)
+	assert.Equal(t, "tg1", validationErrors[2].TargetGroupID)
+	assert.Equal(t, "unknownOperator", validationErrors[2].Err.Operator)
+	assert.Equal(t, `target group tg1: unknownOperator: unknown operator in 'unknownOperator("x")'`, validationErrors[2].Error())
+}
+
+func TestConfigurationValidateWillAcceptValidConfiguration(t *testing.T) {
+	config := configuration.NewConfiguration(
+		[]*model.ExperimentModel{
+			model.NewExperimentModel("1", "exp1", `ifThen(isInTargetGroup("tg1"), "a", flagValue("flag2"))`, false, []string{"flag1"}, nil),
+			model.NewExperimentModel("2", "exp2", `isInPercentage(50, mergeSeed("2", "seed"))`, false, []string{"flag2"}, nil),
+		},
+		[]*model.TargetGroupModel{model.NewTargetGroupModel("tg1", `gte(property("age"), 18)`)},
+		time.Now())
+
+	assert.Empty(t, config.Validate(newExpressionParser(), nil))
+}
+
+func TestConfigurationValidateWillAcceptFlagsRegisteredInCode(t *testing.T) {
+	config := configuration.NewConfiguration(
+		[]*model.ExperimentModel{
+			model.NewExperimentModel("1", "exp1", `eq(flagValue("registered"), flagValue("unknown"))`, false, []string{"flag1"}, nil),
+		},
+		nil,
+		time.Now())
+	flagRepository := repositories.NewFlagRepository()
+	flagRepository.AddFlag(entities.NewFlag(false), "registered")
+
+	validationErrors := config.Validate(newExpressionParser(), flagRepository)
+
+	assert.Equal(t, 1, len(validationErrors))
+	assert.Equal(t, "unknown flag unknown", validationErrors[0].Err.Message)
+}
+
+// newExpressionParser returns a parser with the operators configurations are evaluated with
+func newExpressionParser() roxx.Parser {
+	parser := roxx.NewParser()
+	extensions.NewExperimentsExtensions(parser, nil, nil, nil).Extend()
+	extensions.NewPropertiesExtensions(parser, nil, nil).Extend()
+	return parser
+}
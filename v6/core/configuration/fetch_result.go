@@ -11,6 +11,9 @@ const (
 	SourceCDN Source = iota
 	SourceAPI
 	SourceRoxy
+	SourceEmbedded
+	SourceLocalStorage
+	SourceFile
 )
 
 func (cs Source) String() string {
@@ -21,6 +24,12 @@ func (cs Source) String() string {
 		return "API"
 	case SourceRoxy:
 		return "Roxy"
+	case SourceEmbedded:
+		return "Embedded"
+	case SourceLocalStorage:
+		return "LocalStorage"
+	case SourceFile:
+		return "File"
 	}
 	return strconv.Itoa(int(cs))
 }
@@ -28,6 +37,8 @@ func (cs Source) String() string {
 type FetchResult struct {
 	ParsedData jsonConfiguration
 	Source     Source
+	// NotModified is set when the fetched configuration is identical to the previously fetched one
+	NotModified bool
 }
 
 func NewFetchResult(data string, source Source) *FetchResult {
@@ -43,3 +54,11 @@ func NewFetchResult(data string, source Source) *FetchResult {
 
 	return &FetchResult{ParsedData: parsedData, Source: source}
 }
+
+func (fr *FetchResult) JSON() (string, error) {
+	data, err := json.Marshal(fr.ParsedData)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -11,16 +11,28 @@ import (
 type FetchedInvoker struct {
 	fetchedHandlers []model.ConfigurationFetchedHandler
 	handlersMutex   sync.RWMutex
+	logger          logging.Logger
 }
 
 func NewFetchedInvoker() *FetchedInvoker {
-	return &FetchedInvoker{}
+	return NewFetchedInvokerWithLogger(nil)
+}
+
+// NewFetchedInvokerWithLogger is like NewFetchedInvoker, a configuration fetched handler that panics is logged to logger
+func NewFetchedInvokerWithLogger(logger logging.Logger) *FetchedInvoker {
+	return &FetchedInvoker{logger: logging.OrGlobal(logger)}
 }
 
 func (cfi *FetchedInvoker) Invoke(fetcherStatus model.FetcherStatus, creationDate time.Time, hasChanges bool) {
 	cfi.raiseFetchedEvent(model.NewConfigurationFetchedArgs(fetcherStatus, creationDate, hasChanges))
 }
 
+func (cfi *FetchedInvoker) InvokeWithChanges(fetcherStatus model.FetcherStatus, creationDate time.Time, hasChanges bool, changes model.ConfigurationChanges) {
+	args := model.NewConfigurationFetchedArgs(fetcherStatus, creationDate, hasChanges)
+	args.Changes = changes
+	cfi.raiseFetchedEvent(args)
+}
+
 func (cfi *FetchedInvoker) InvokeError(errorDetails model.FetcherError) {
 	cfi.raiseFetchedEvent(model.NewErrorConfigurationFetchedArgs(errorDetails))
 }
@@ -39,7 +51,7 @@ func (cfi *FetchedInvoker) raiseFetchedEvent(args model.ConfigurationFetchedArgs
 
 	defer func() {
 		if r := recover(); r != nil {
-			logging.GetLogger().Error("Failed to execute fetched handler, panic", r)
+			cfi.logger.Error("Failed to execute fetched handler, panic", r)
 		}
 	}()
 	for _, handler := range handlers {
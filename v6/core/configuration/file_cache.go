@@ -0,0 +1,46 @@
+package configuration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/rollout/rox-go/v6/core/model"
+)
+
+type fileCache struct {
+	path string
+}
+
+func NewFileCache(path string) model.ConfigurationCache {
+	return &fileCache{path: path}
+}
+
+func (c *fileCache) Load() (string, error) {
+	data, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (c *fileCache) Save(configuration string) error {
+	// write to a temporary file first so a crash never leaves a truncated cache behind
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), filepath.Base(c.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(configuration); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
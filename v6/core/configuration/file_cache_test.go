@@ -0,0 +1,41 @@
+package configuration_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCacheWillReturnEmptyWhenFileIsMissing(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "rox-cache")
+	defer os.RemoveAll(dir)
+
+	cache := configuration.NewFileCache(filepath.Join(dir, "configuration.json"))
+	data, err := cache.Load()
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", data)
+}
+
+func TestFileCacheWillLoadSavedFetchResult(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "rox-cache")
+	defer os.RemoveAll(dir)
+
+	result := configuration.NewFetchResult(`{"data": "harti", "signature_v0": "sig", "signed_date": "2018-01-09T19:02:00.720Z"}`, configuration.SourceCDN)
+	data, err := result.JSON()
+	assert.Nil(t, err)
+
+	cache := configuration.NewFileCache(filepath.Join(dir, "configuration.json"))
+	assert.Nil(t, cache.Save(data))
+
+	loaded, err := cache.Load()
+	assert.Nil(t, err)
+
+	loadedResult := configuration.NewFetchResult(loaded, configuration.SourceLocalStorage)
+	assert.Equal(t, result.ParsedData, loadedResult.ParsedData)
+	assert.Equal(t, configuration.SourceLocalStorage, loadedResult.Source)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/rollout/rox-go/v6/core/logging"
 	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/roxx"
 	"github.com/rollout/rox-go/v6/core/security"
 )
 
@@ -14,20 +15,26 @@ type Parser struct {
 	signatureVerifier security.SignatureVerifier
 	errorReporter     model.ErrorReporter
 	fetchedInvoker    *FetchedInvoker
+	expressionParser  roxx.Parser
+	flagRepository    model.FlagRepository
+	logger            logging.Logger
 }
 
-func NewParser(signatureVerifier security.SignatureVerifier, errorReporter model.ErrorReporter, fetchedInvoker *FetchedInvoker) *Parser {
+func NewParser(signatureVerifier security.SignatureVerifier, errorReporter model.ErrorReporter, fetchedInvoker *FetchedInvoker, expressionParser roxx.Parser, flagRepository model.FlagRepository, logger logging.Logger) *Parser {
 	return &Parser{
 		signatureVerifier: signatureVerifier,
 		errorReporter:     errorReporter,
 		fetchedInvoker:    fetchedInvoker,
+		expressionParser:  expressionParser,
+		flagRepository:    flagRepository,
+		logger:            logging.OrGlobal(logger),
 	}
 }
 
 func (cp *Parser) Parse(fetchResult *FetchResult, sdkSettings model.SdkSettings) (configuration *Configuration) {
 	defer func() {
 		if r := recover(); r != nil {
-			logging.GetLogger().Error("Failed to parse configurations", r)
+			cp.logger.Error("Failed to parse configurations", r)
 			cp.fetchedInvoker.InvokeError(model.FetcherErrorUnknown)
 			configuration = nil
 		}
@@ -67,7 +74,12 @@ func (cp *Parser) Parse(fetchResult *FetchResult, sdkSettings model.SdkSettings)
 	experiments := cp.parseExperiments(internalJSONConf)
 	groups := cp.parseGroups(internalJSONConf)
 
-	return NewConfiguration(experiments, groups, signatureDate)
+	configuration = NewConfiguration(experiments, groups, signatureDate)
+	configuration.ValidationErrors = configuration.Validate(cp.expressionParser, cp.flagRepository)
+	for _, validationError := range configuration.ValidationErrors {
+		cp.logger.Debug(fmt.Sprintf("Invalid configuration condition: %s", validationError), nil)
+	}
+	return configuration
 }
 
 func (cp *Parser) parseExperiments(internalJSONConf jsonInternalConfiguration) []*model.ExperimentModel {
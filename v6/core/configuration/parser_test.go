@@ -8,6 +8,7 @@ import (
 	"github.com/rollout/rox-go/v6/core/configuration"
 	"github.com/rollout/rox-go/v6/core/mocks"
 	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/roxx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -41,7 +42,7 @@ func TestConfigurationParserWillReturnNullWhenUnexpectedException(t *testing.T)
 		cfiEvent = e
 	})
 
-	cp := configuration.NewParser(sf, nil, cfi)
+	cp := configuration.NewParser(sf, nil, cfi, roxx.NewParser(), nil, nil)
 	conf := cp.Parse(configFetchResult, sdkSettings)
 
 	assert.Nil(t, conf)
@@ -77,7 +78,7 @@ func TestConfigurationParserWillReturnNullWhenWrongSignature(t *testing.T) {
 		cfiEvent = e
 	})
 
-	cp := configuration.NewParser(sf, errRe, cfi)
+	cp := configuration.NewParser(sf, errRe, cfi, roxx.NewParser(), nil, nil)
 	conf := cp.Parse(configFetchResult, nil)
 
 	assert.Nil(t, conf)
@@ -117,7 +118,7 @@ func TestConfigurationParserWillReturnNullWhenWrongApiKey(t *testing.T) {
 		cfiEvent = e
 	})
 
-	cp := configuration.NewParser(sf, errRe, cfi)
+	cp := configuration.NewParser(sf, errRe, cfi, roxx.NewParser(), nil, nil)
 	conf := cp.Parse(configFetchResult, sdkSettings)
 
 	assert.Nil(t, conf)
@@ -158,7 +159,7 @@ func TestConfigurationParserWillParseExperimentsAndTargetGroups(t *testing.T) {
 		cfiEvent = e
 	})
 
-	cp := configuration.NewParser(sf, errRe, cfi)
+	cp := configuration.NewParser(sf, errRe, cfi, roxx.NewParser(), nil, nil)
 	conf := cp.Parse(configFetchResult, sdkSettings)
 
 	assert.NotNil(t, conf)
@@ -185,6 +186,11 @@ func TestConfigurationParserWillParseExperimentsAndTargetGroups(t *testing.T) {
 	assert.Equal(t, "Invitations.isInvitationsEnabled", conf.Experiments[1].Flags[0])
 	assert.Equal(t, 0, len(conf.Experiments[1].Labels))
 
+	assert.Equal(t, 2, len(conf.ValidationErrors))
+	assert.Equal(t, "1", conf.ValidationErrors[0].ExperimentID)
+	assert.Equal(t, "ifThen", conf.ValidationErrors[0].Err.Operator)
+	assert.Equal(t, "2", conf.ValidationErrors[1].ExperimentID)
+
 	assert.Nil(t, cfiEvent)
 }
 
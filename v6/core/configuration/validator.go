@@ -0,0 +1,71 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/roxx"
+)
+
+// ValidationError is a problem found in a condition of the configuration
+type ValidationError struct {
+	// ExperimentID or TargetGroupID is set to the owner of the condition
+	ExperimentID  string
+	TargetGroupID string
+	Err           *roxx.ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	if e.ExperimentID != "" {
+		return fmt.Sprintf("experiment %s: %s", e.ExperimentID, e.Err)
+	}
+	return fmt.Sprintf("target group %s: %s", e.TargetGroupID, e.Err)
+}
+
+// Validate checks every condition of the configuration against the operators of parser with roxx.Validate,
+// and that the target groups they refer to are part of the configuration. The flags they refer to are either
+// part of the configuration or registered in flagRepository, which can be nil
+func (c *Configuration) Validate(parser roxx.Parser, flagRepository model.FlagRepository) []*ValidationError {
+	targetGroups := make(map[string]bool)
+	for _, targetGroup := range c.TargetGroups {
+		targetGroups[targetGroup.ID] = true
+	}
+	flags := make(map[string]bool)
+	for _, experiment := range c.Experiments {
+		for _, flag := range experiment.Flags {
+			flags[flag] = true
+		}
+	}
+
+	var validationErrors []*ValidationError
+	validate := func(condition string, newError func(err *roxx.ValidationError) *ValidationError) {
+		analysis := roxx.Analyze(parser, condition)
+		for _, err := range analysis.Errors {
+			validationErrors = append(validationErrors, newError(err))
+		}
+		for _, targetGroup := range analysis.References["isInTargetGroup"] {
+			if !targetGroups[targetGroup] {
+				validationErrors = append(validationErrors, newError(&roxx.ValidationError{Expression: condition, Operator: "isInTargetGroup", Message: fmt.Sprintf("unknown target group %s", targetGroup)}))
+			}
+		}
+		for _, flag := range analysis.References["flagValue"] {
+			if !flags[flag] && (flagRepository == nil || flagRepository.GetFlag(flag) == nil) {
+				validationErrors = append(validationErrors, newError(&roxx.ValidationError{Expression: condition, Operator: "flagValue", Message: fmt.Sprintf("unknown flag %s", flag)}))
+			}
+		}
+	}
+
+	for _, experiment := range c.Experiments {
+		id := experiment.ID
+		validate(experiment.Condition, func(err *roxx.ValidationError) *ValidationError {
+			return &ValidationError{ExperimentID: id, Err: err}
+		})
+	}
+	for _, targetGroup := range c.TargetGroups {
+		id := targetGroup.ID
+		validate(targetGroup.Condition, func(err *roxx.ValidationError) *ValidationError {
+			return &ValidationError{TargetGroupID: id, Err: err}
+		})
+	}
+	return validationErrors
+}
@@ -3,3 +3,22 @@ package context
 type Context interface {
 	Get(key string) interface{}
 }
+
+// valueContext is implemented by the contexts that can hold keys which are not strings
+type valueContext interface {
+	Value(key interface{}) interface{}
+}
+
+// Value returns the value ctx holds for key, which unlike with Get does not have to be a string
+func Value(ctx Context, key interface{}) interface{} {
+	if ctx == nil {
+		return nil
+	}
+	if vc, ok := ctx.(valueContext); ok {
+		return vc.Value(key)
+	}
+	if key, ok := key.(string); ok {
+		return ctx.Get(key)
+	}
+	return nil
+}
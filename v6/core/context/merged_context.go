@@ -22,3 +22,10 @@ func (mc *MergedContext) Get(key string) interface{} {
 
 	return nil
 }
+
+func (mc *MergedContext) Value(key interface{}) interface{} {
+	if item := Value(mc.localContext, key); item != nil {
+		return item
+	}
+	return Value(mc.globalContext, key)
+}
@@ -0,0 +1,30 @@
+package context
+
+type ValueContext struct {
+	parent Context
+	key    interface{}
+	value  interface{}
+}
+
+// NewValueContext returns a context holding value for key, the other keys are looked up in parent.
+// Get only finds string keys, a key of an unexported type is found with Value alone and can not collide with the user's keys
+func NewValueContext(parent Context, key interface{}, value interface{}) Context {
+	return &ValueContext{parent: parent, key: key, value: value}
+}
+
+func (vc *ValueContext) Get(key string) interface{} {
+	if vc.key == key {
+		return vc.value
+	}
+	if vc.parent != nil {
+		return vc.parent.Get(key)
+	}
+	return nil
+}
+
+func (vc *ValueContext) Value(key interface{}) interface{} {
+	if vc.key == key {
+		return vc.value
+	}
+	return Value(vc.parent, key)
+}
@@ -0,0 +1,43 @@
+package context_test
+
+import (
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueContextWillFallBackToParent(t *testing.T) {
+	parent := context.NewContext(map[string]interface{}{
+		"a": 1,
+		"b": 2,
+	})
+
+	valueContext := context.NewValueContext(parent, "b", 3)
+
+	assert.Equal(t, 1, valueContext.Get("a"))
+	assert.Equal(t, 3, valueContext.Get("b"))
+	assert.Equal(t, nil, valueContext.Get("c"))
+}
+
+func TestValueContextWithNullParent(t *testing.T) {
+	valueContext := context.NewValueContext(nil, "a", 1)
+
+	assert.Equal(t, 1, valueContext.Get("a"))
+	assert.Equal(t, nil, valueContext.Get("b"))
+}
+
+func TestValueContextWillHideKeysThatAreNotStrings(t *testing.T) {
+	type key string
+	parent := context.NewContext(map[string]interface{}{
+		"a": 1,
+	})
+	valueContext := context.NewValueContext(parent, key("a"), 2)
+
+	assert.Equal(t, 1, valueContext.Get("a"))
+	assert.Equal(t, 2, context.Value(valueContext, key("a")))
+	assert.Equal(t, 1, context.Value(valueContext, "a"))
+	assert.Equal(t, 2, context.Value(context.NewMergedContext(parent, valueContext), key("a")))
+	assert.Equal(t, 2, context.Value(context.NewMergedContext(valueContext, nil), key("a")))
+	assert.Equal(t, nil, context.Value(parent, key("a")))
+}
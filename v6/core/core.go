@@ -1,8 +1,12 @@
 package core
 
 import (
+	gocontext "context"
+	"fmt"
 	"net/http"
 	"regexp"
+	"sync"
+	"time"
 
 	uuid "github.com/google/uuid"
 
@@ -16,6 +20,7 @@ import (
 	"github.com/rollout/rox-go/v6/core/entities"
 	"github.com/rollout/rox-go/v6/core/extensions"
 	"github.com/rollout/rox-go/v6/core/impression"
+	"github.com/rollout/rox-go/v6/core/logging"
 	"github.com/rollout/rox-go/v6/core/model"
 	"github.com/rollout/rox-go/v6/core/network"
 	"github.com/rollout/rox-go/v6/core/notifications"
@@ -31,9 +36,9 @@ type Core struct {
 	registerer                   *register.Registerer
 	flagRepository               model.FlagRepository
 	customPropertyRepository     model.CustomPropertyRepository
-	experimentRepository         model.ExperimentRepository
-	targetGroupRepository        model.TargetGroupRepository
+	configurationRepository      model.ConfigurationRepository
 	flagSetter                   *entities.FlagSetter
+	flagChangeNotifier           *entities.FlagChangeNotifier
 	parser                       roxx.Parser
 	impressionInvoker            model.ImpressionInvoker
 	analyticsHandler             model.Analytics
@@ -43,47 +48,92 @@ type Core struct {
 	configurationFetcher         network.ConfigurationFetcher
 	errorReporter                model.ErrorReporter
 	lastConfigurations           *configuration.FetchResult
+	lastSignatureDate            time.Time
+	appliedConfiguration         *configuration.Configuration
+	configurationMutex           sync.Mutex
 	internalFlags                model.InternalFlags
 	pushUpdatesListener          *notifications.NotificationListener
 	environment                  model.Environment
 	disableSignatureVerification bool
+	configurationCache           model.ConfigurationCache
+	offline                      bool
+	httpClient                   *http.Client
+	logger                       *logging.InstanceLogger
 	quit                         chan struct{}
 }
 
 const invalidAPIKeyErrorMessage = "Invalid rollout apikey"
 
 func NewCore() *Core {
-	parser := roxx.NewParser()
-	flagRepository := repositories.NewFlagRepository()
-	targetGroupRepository := repositories.NewTargetGroupRepository()
-	experimentRepository := repositories.NewExperimentRepository()
-	customPropertyRepository := repositories.NewCustomPropertyRepository()
+	// subsystems are created before the options are known, so they get a logger that Setup can redirect
+	logger := logging.NewInstanceLogger()
+	parser := roxx.NewParserWithLogger(logger)
+	flagRepository := repositories.NewFlagRepositoryWithLogger(logger)
+	configurationRepository := repositories.NewConfigurationRepository()
+	customPropertyRepository := repositories.NewCustomPropertyRepositoryWithLogger(logger)
+	configurationFetchedInvoker := configuration.NewFetchedInvokerWithLogger(logger)
+	flagChangeNotifier := entities.NewFlagChangeNotifier(flagRepository, logger)
+	configurationFetchedInvoker.RegisterFetchedHandler(func(args *model.ConfigurationFetchedArgs) {
+		// unchanged configurations can not change flag values, SetContext notifies about global context changes
+		if args.FetcherStatus != model.FetcherStatusErrorFetchedFailed && args.HasChanges {
+			flagChangeNotifier.Notify()
+		}
+	})
 
 	return &Core{
 		flagRepository:              flagRepository,
 		customPropertyRepository:    customPropertyRepository,
-		targetGroupRepository:       targetGroupRepository,
-		experimentRepository:        experimentRepository,
+		configurationRepository:     configurationRepository,
 		parser:                      parser,
-		configurationFetchedInvoker: configuration.NewFetchedInvoker(),
+		configurationFetchedInvoker: configurationFetchedInvoker,
+		flagChangeNotifier:          flagChangeNotifier,
 		registerer:                  register.NewRegisterer(flagRepository),
+		logger:                      logger,
 		quit:                        make(chan struct{}),
 	}
 }
 
 func (core *Core) Setup(sdkSettings model.SdkSettings, deviceProperties model.DeviceProperties, roxOptions model.RoxOptions) <-chan struct{} {
+	return core.SetupContext(gocontext.Background(), sdkSettings, deviceProperties, roxOptions)
+}
+
+// SetupContext is like Setup, but the initial configuration fetch is bound to ctx
+func (core *Core) SetupContext(ctx gocontext.Context, sdkSettings model.SdkSettings, deviceProperties model.DeviceProperties, roxOptions model.RoxOptions) <-chan struct{} {
 	core.sdkSettings = sdkSettings
+	if roxOptions != nil {
+		core.logger.SetLogger(roxOptions.Logger())
+		if handler := roxOptions.EvaluationErrorHandler(); handler != nil {
+			core.parser.SetEvaluationErrorHandler(func(err *roxx.EvaluationError) {
+				handler(err)
+			})
+		} else {
+			core.parser.SetEvaluationErrorHandler(nil)
+		}
+		core.parser.SetStrictEvaluation(roxOptions.IsStrictEvaluationEnabled())
+	}
 
 	roxyPath := ""
 	if roxOptions != nil && roxOptions.RoxyURL() != "" {
 		roxyPath = roxOptions.RoxyURL()
 	}
 
+	configurationFilePath := ""
 	if roxOptions != nil {
 		core.disableSignatureVerification = roxOptions.IsSignatureVerificationDisabled()
+		configurationFilePath = roxOptions.ConfigurationFilePath()
+	}
+	core.offline = configurationFilePath != ""
+
+	core.httpClient = http.DefaultClient
+	if roxOptions != nil && roxOptions.HTTPClient() != nil {
+		core.httpClient = roxOptions.HTTPClient()
+	}
+	if roxOptions != nil {
+		core.httpClient = network.NewInterceptingClient(core.httpClient, roxOptions.RequestInterceptors(), roxOptions.ResponseInterceptors())
 	}
 	envApi := consts.ROLLOUT_API
-	if roxyPath == "" {
+	// offline instances never reach Rollout, the key only has to match the application of the configuration file
+	if roxyPath == "" && !core.offline {
 		validMongoIdPattern := "^[a-f\\d]{24}$"
 		// Try to parse it as a mongo ID (rollout.io)
 		matched, err := regexp.Match(validMongoIdPattern, []byte(sdkSettings.APIKey()))
@@ -107,19 +157,21 @@ func (core *Core) Setup(sdkSettings model.SdkSettings, deviceProperties model.De
 		core.environment = client.NewSaasEnvironment(envApi)
 	}
 
-	core.internalFlags = client.NewInternalFlags(core.experimentRepository, core.parser, core.environment)
+	core.internalFlags = client.NewInternalFlags(core.configurationRepository, core.parser, core.environment)
 	impressionDeps := &impression.ImpressionsDeps{
 		InternalFlags:            core.internalFlags,
 		CustomPropertyRepository: core.customPropertyRepository,
 		DeviceProperties:         deviceProperties,
 		IsRoxy:                   roxyPath != "",
+		Logger:                   core.logger,
 	}
-	analyticsEnabled := roxOptions != nil && !roxOptions.IsAnalyticsReportingDisabled() && !impressionDeps.IsRoxy
+	analyticsEnabled := roxOptions != nil && !roxOptions.IsAnalyticsReportingDisabled() && !impressionDeps.IsRoxy && !core.offline
 	if analyticsEnabled {
 		analyticsHandler := analytics.NewAnalyticsHandler(&analytics.AnalyticsDeps{
 			UriPath:          core.environment.EnvironmentAnalyticsPath(),
-			Request:          network.NewRequest(http.DefaultClient),
+			Request:          network.NewRequest(core.httpClient),
 			DeviceProperties: deviceProperties,
+			Logger:           core.logger,
 			FlushAtSize:      roxOptions.AnalyticsQueueSize(),
 		})
 		impressionDeps.Analytics = analyticsHandler
@@ -128,10 +180,10 @@ func (core *Core) Setup(sdkSettings model.SdkSettings, deviceProperties model.De
 	}
 	core.impressionInvoker = impression.NewImpressionInvoker(impressionDeps)
 
-	core.flagSetter = entities.NewFlagSetter(core.flagRepository, core.parser, core.experimentRepository, core.impressionInvoker)
+	core.flagSetter = entities.NewFlagSetter(core.flagRepository, core.parser, core.configurationRepository, core.impressionInvoker)
 	buid := client.NewBUID(sdkSettings, deviceProperties, core.flagRepository, core.customPropertyRepository)
 
-	experimentsExtensions := extensions.NewExperimentsExtensions(core.parser, core.targetGroupRepository, core.flagRepository, core.experimentRepository)
+	experimentsExtensions := extensions.NewExperimentsExtensions(core.parser, core.configurationRepository, core.flagRepository, core.configurationRepository)
 	var dynamicPropertyRuleHandler model.DynamicPropertyRuleHandler
 	if roxOptions != nil {
 		dynamicPropertyRuleHandler = roxOptions.DynamicPropertyRuleHandler()
@@ -142,18 +194,17 @@ func (core *Core) Setup(sdkSettings model.SdkSettings, deviceProperties model.De
 
 	requestConfigBuilder := network.NewRequestConfigurationBuilder(sdkSettings, buid, deviceProperties, roxyPath, core.environment)
 
-	// TODO http client
-	clientRequest := network.NewRequest(http.DefaultClient)
+	clientRequest := network.NewRequest(core.httpClient)
+	errReporterRequest := network.NewRequest(core.httpClient)
+	core.errorReporter = reporting.NewErrorReporter(core.environment, errReporterRequest, deviceProperties, buid, core.logger)
 
-	// TODO http client
-	errReporterRequest := network.NewRequest(http.DefaultClient)
-	core.errorReporter = reporting.NewErrorReporter(core.environment, errReporterRequest, deviceProperties, buid)
-
-	if roxyPath != "" {
-		core.configurationFetcher = network.NewConfigurationFetcherRoxy(requestConfigBuilder, clientRequest, core.configurationFetchedInvoker)
+	if core.offline {
+		core.configurationFetcher = network.NewConfigurationFetcherFile(configurationFilePath, network.DefaultFilePollInterval, core.configurationFetchedInvoker, core.logger)
+	} else if roxyPath != "" {
+		core.configurationFetcher = network.NewConfigurationFetcherRoxy(requestConfigBuilder, clientRequest, core.configurationFetchedInvoker, core.logger)
 	} else {
-		core.stateSender = network.NewStateSender(clientRequest, deviceProperties, core.flagRepository, core.customPropertyRepository, core.environment, core.disableSignatureVerification)
-		core.configurationFetcher = network.NewConfigurationFetcher(core.environment, requestConfigBuilder, clientRequest, core.configurationFetchedInvoker)
+		core.stateSender = network.NewStateSender(clientRequest, deviceProperties, core.flagRepository, core.customPropertyRepository, core.environment, core.disableSignatureVerification, core.logger)
+		core.configurationFetcher = network.NewConfigurationFetcher(core.environment, requestConfigBuilder, clientRequest, core.configurationFetchedInvoker, core.logger)
 	}
 
 	var configurationFetchedHandler model.ConfigurationFetchedHandler
@@ -162,19 +213,33 @@ func (core *Core) Setup(sdkSettings model.SdkSettings, deviceProperties model.De
 	}
 	core.configurationFetchedInvoker.RegisterFetchedHandler(core.wrapConfigurationFetchedHandler(configurationFetchedHandler))
 
+	if roxOptions != nil {
+		core.configurationCache = roxOptions.ConfigurationCache()
+	}
+
+	// the cached configuration was fetched after the embedded one was built, so it takes precedence
+	if !core.applyCachedConfiguration() && roxOptions != nil && roxOptions.EmbeddedConfiguration() != "" {
+		core.applyEmbeddedConfiguration(roxOptions.EmbeddedConfiguration())
+	}
+
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		<-core.Fetch()
+		_, err := core.FetchContext(ctx)
 
 		if roxOptions != nil && roxOptions.ImpressionHandler() != nil {
 			core.impressionInvoker.RegisterImpressionHandler(roxOptions.ImpressionHandler())
 		}
 
-		if roxOptions != nil && roxOptions.FetchInterval() != 0 {
-			utils.RunPeriodicTask(func() {
+		if watcher, ok := core.configurationFetcher.(network.ConfigurationWatcher); ok {
+			watcher.Watch(func() {
 				<-core.Fetch()
-			}, roxOptions.FetchInterval(), core.quit)
+			}, core.quit)
+		} else if roxOptions != nil && roxOptions.FetchInterval() != 0 {
+			utils.RunPeriodicTaskWithBackoff(func() bool {
+				_, err := core.FetchContext(gocontext.Background())
+				return err == nil
+			}, roxOptions.FetchInterval(), newFetchBackoff(roxOptions.FetchRetryPolicy()), err == nil, core.quit)
 		}
 		if core.stateSender != nil {
 			core.stateSender.Send()
@@ -183,44 +248,152 @@ func (core *Core) Setup(sdkSettings model.SdkSettings, deviceProperties model.De
 	return done
 }
 
+// newFetchBackoff creates the backoff for retrying failed fetches, the default policy fills in what policy does not set
+func newFetchBackoff(policy *model.FetchRetryPolicy) *utils.Backoff {
+	defaultPolicy := model.DefaultFetchRetryPolicy()
+	if policy == nil {
+		policy = defaultPolicy
+	}
+	jitter := *defaultPolicy.Jitter
+	if policy.Jitter != nil {
+		jitter = *policy.Jitter
+	}
+	return utils.NewBackoff(policy.InitialDelay, policy.MaxDelay, policy.Multiplier, jitter)
+}
+
 func (core *Core) Fetch() <-chan struct{} {
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		select {
-		default:
-			if core.configurationFetcher == nil {
-				return
-			}
+		core.FetchContext(gocontext.Background())
+	}()
+	return done
+}
 
-			result := core.configurationFetcher.Fetch()
-			if result == nil {
-				return
-			}
+// FetchContext fetches and applies the configuration, returning the status it was applied with
+func (core *Core) FetchContext(ctx gocontext.Context) (model.FetcherStatus, error) {
+	select {
+	case <-core.quit:
+		return model.FetcherStatusErrorFetchedFailed, fmt.Errorf("core has been shut down")
+	default:
+	}
 
-			var signatureVerifier security.SignatureVerifier
-			if core.disableSignatureVerification {
-				signatureVerifier = security.NewDisabledSignatureVerifier()
-			} else {
-				signatureVerifier = security.NewSignatureVerifier(core.environment)
-			}
-			configurationParser := configuration.NewParser(signatureVerifier, core.errorReporter, core.configurationFetchedInvoker)
-			config := configurationParser.Parse(result, core.sdkSettings)
-			if config != nil {
-				core.experimentRepository.SetExperiments(config.Experiments)
-				core.targetGroupRepository.SetTargetGroups(config.TargetGroups)
-				core.flagSetter.SetExperiments()
-
-				hasChanges := core.lastConfigurations == nil || *core.lastConfigurations != *result
-				core.lastConfigurations = result
-				core.configurationFetchedInvoker.Invoke(model.FetcherStatusAppliedFromNetwork, config.SignatureDate, hasChanges)
-			}
-			return
-		case <-core.quit:
-			return
+	if core.configurationFetcher == nil {
+		return model.FetcherStatusErrorFetchedFailed, fmt.Errorf("core has not been set up")
+	}
+
+	result := core.configurationFetcher.FetchContext(ctx)
+	if result == nil {
+		if ctx.Err() != nil {
+			return model.FetcherStatusErrorFetchedFailed, ctx.Err()
 		}
-	}()
-	return done
+		return model.FetcherStatusErrorFetchedFailed, fmt.Errorf("failed to fetch configuration")
+	}
+
+	fetcherStatus := model.FetcherStatusAppliedFromNetwork
+	if result.Source == configuration.SourceFile {
+		fetcherStatus = model.FetcherStatusAppliedFromFile
+	}
+
+	core.configurationMutex.Lock()
+	isApplied := core.lastConfigurations != nil && core.lastConfigurations.ParsedData == result.ParsedData
+	lastSignatureDate := core.lastSignatureDate
+	core.configurationMutex.Unlock()
+	if result.NotModified && isApplied {
+		// the applied configuration is already up to date, skip verifying and parsing it again
+		core.configurationFetchedInvoker.Invoke(fetcherStatus, lastSignatureDate, false)
+		return fetcherStatus, nil
+	}
+
+	if !core.applyConfiguration(result, fetcherStatus) {
+		return model.FetcherStatusErrorFetchedFailed, fmt.Errorf("failed to apply configuration from %s", result.Source)
+	}
+	return fetcherStatus, nil
+}
+
+func (core *Core) applyEmbeddedConfiguration(data string) {
+	result := configuration.NewFetchResult(data, configuration.SourceEmbedded)
+	if result == nil {
+		core.logger.Error("Failed to parse embedded configuration", nil)
+		core.configurationFetchedInvoker.InvokeError(model.FetcherErrorCorruptedJSON)
+		return
+	}
+	core.applyConfiguration(result, model.FetcherStatusAppliedFromEmbedded)
+}
+
+func (core *Core) applyCachedConfiguration() bool {
+	if core.configurationCache == nil {
+		return false
+	}
+
+	data, err := core.configurationCache.Load()
+	if err != nil {
+		core.logger.Warn("Failed to load cached configuration", err)
+		return false
+	}
+
+	result := configuration.NewFetchResult(data, configuration.SourceLocalStorage)
+	if result == nil {
+		return false
+	}
+	return core.applyConfiguration(result, model.FetcherStatusAppliedFromLocalStorage)
+}
+
+func (core *Core) storeConfiguration(result *configuration.FetchResult) {
+	// roxy configurations are not signed, so there is nothing to verify them against on the next load
+	if core.configurationCache == nil || result.Source == configuration.SourceRoxy {
+		return
+	}
+
+	data, err := result.JSON()
+	if err == nil {
+		err = core.configurationCache.Save(data)
+	}
+	if err != nil {
+		core.logger.Warn("Failed to store configuration in cache", err)
+	}
+}
+
+func (core *Core) applyConfiguration(result *configuration.FetchResult, fetcherStatus model.FetcherStatus) bool {
+	var signatureVerifier security.SignatureVerifier
+	if core.disableSignatureVerification {
+		signatureVerifier = security.NewDisabledSignatureVerifier()
+	} else {
+		signatureVerifier = security.NewSignatureVerifier(core.environment)
+	}
+	configurationParser := configuration.NewParser(signatureVerifier, core.errorReporter, core.configurationFetchedInvoker, core.parser, core.flagRepository, core.logger)
+	config := configurationParser.Parse(result, core.sdkSettings)
+	if config == nil {
+		return false
+	}
+
+	// flags compile their conditions when they are set, target groups are looked up in the parser's cache when evaluated
+	for _, targetGroup := range config.TargetGroups {
+		core.parser.CompileExpression(targetGroup.Condition)
+	}
+
+	hasChanges, changes := core.setConfiguration(result, config)
+	if hasChanges && fetcherStatus == model.FetcherStatusAppliedFromNetwork {
+		core.storeConfiguration(result)
+	}
+	core.configurationFetchedInvoker.InvokeWithChanges(fetcherStatus, config.SignatureDate, hasChanges, changes)
+	return true
+}
+
+// setConfiguration applies config to the repositories and flags, concurrent fetches must not interleave,
+// otherwise flags could end up with conditions from different configurations
+func (core *Core) setConfiguration(result *configuration.FetchResult, config *configuration.Configuration) (hasChanges bool, changes model.ConfigurationChanges) {
+	core.configurationMutex.Lock()
+	defer core.configurationMutex.Unlock()
+
+	core.configurationRepository.SetConfiguration(config.Experiments, config.TargetGroups)
+	core.flagSetter.SetExperiments()
+	hasChanges = core.lastConfigurations == nil || core.lastConfigurations.ParsedData != result.ParsedData
+	changes = config.Changes(core.appliedConfiguration)
+	core.lastConfigurations = result
+	core.appliedConfiguration = config
+	core.lastSignatureDate = config.SignatureDate
+	return hasChanges, changes
 }
 
 func (core *Core) Register(ns string, roxContainer interface{}) {
@@ -231,6 +404,26 @@ func (core *Core) SetContext(ctx context.Context) {
 	for _, flag := range core.flagRepository.GetAllFlags() {
 		flag.(model.InternalVariant).SetContext(ctx)
 	}
+	core.flagChangeNotifier.Notify()
+}
+
+// Logger returns the logger of this instance, it uses the global logger until Setup is called with a logger
+func (core *Core) Logger() logging.Logger {
+	return core.logger
+}
+
+// Explain evaluates the flag named flagName for ctx and describes how its value was computed
+func (core *Core) Explain(flagName string, ctx context.Context) (*model.FlagExplanation, error) {
+	flag := core.flagRepository.GetFlag(flagName)
+	if flag == nil {
+		return nil, fmt.Errorf("flag %s is not registered", flagName)
+	}
+	return flag.(model.InternalVariant).Explain(ctx), nil
+}
+
+// OnFlagChanged calls handler whenever an applied configuration or global context changes the value of the flag for ctx
+func (core *Core) OnFlagChanged(flagName string, ctx context.Context, handler model.FlagChangedHandler) {
+	core.flagChangeNotifier.Subscribe(flagName, ctx, handler)
 }
 
 func (core *Core) AddCustomProperty(property *properties.CustomProperty) {
@@ -243,7 +436,7 @@ func (core *Core) AddCustomPropertyIfNotExists(property *properties.CustomProper
 
 func (core *Core) wrapConfigurationFetchedHandler(handler model.ConfigurationFetchedHandler) model.ConfigurationFetchedHandler {
 	return func(args *model.ConfigurationFetchedArgs) {
-		if args.FetcherStatus != model.FetcherStatusErrorFetchedFailed {
+		if args.FetcherStatus != model.FetcherStatusErrorFetchedFailed && !core.offline {
 			core.startOrStopPushUpdatesListener()
 		}
 
@@ -256,7 +449,7 @@ func (core *Core) wrapConfigurationFetchedHandler(handler model.ConfigurationFet
 func (core *Core) startOrStopPushUpdatesListener() {
 
 	if core.pushUpdatesListener == nil {
-		core.pushUpdatesListener = notifications.NewNotificationListener(core.environment.EnvironmentNotificationsPath(), core.sdkSettings.APIKey())
+		core.pushUpdatesListener = notifications.NewNotificationListener(core.environment.EnvironmentNotificationsPath(), core.sdkSettings.APIKey(), core.httpClient, core.logger)
 		core.pushUpdatesListener.On("changed", func(event notifications.Event) {
 			<-core.Fetch()
 		})
@@ -1,12 +1,25 @@
 package core_test
 
 import (
+	gocontext "context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/rollout/rox-go/v6/core"
+	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/rollout/rox-go/v6/core/entities"
+	"github.com/rollout/rox-go/v6/core/logging"
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/roxx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/rollout/rox-go/v6/core/mocks"
 )
@@ -14,26 +27,10 @@ import (
 var validApiKey = "5008ef002000b62ceaaab37b"
 
 func TestCoreWillCheckCoreSetupWhenOptionsWithRoxy(t *testing.T) {
-	sdkSettings := &mocks.SdkSettings{}
-	sdkSettings.On("DevModeSecret").Return("")
-	sdkSettings.On("APIKey").Return(validApiKey)
-
-	deviceProperties := &mocks.DeviceProperties{}
-	deviceProperties.On("GetAllProperties").Return(map[string]string{})
-	deviceProperties.On("DistinctID").Return("")
-
-	options := &mocks.RoxOptions{}
-	options.On("RoxyURL").Return("http://site.com")
-	options.On("FetchInterval").Return(time.Duration(0))
-	options.On("ConfigurationFetchedHandler").Return(nil)
-	options.On("ImpressionHandler").Return(nil)
-	options.On("SelfManagedOptions").Return(nil)
-	options.On("DynamicPropertyRuleHandler").Return(nil)
-	options.On("IsSignatureVerificationDisabled").Return(true)
-	options.On("IsAnalyticsReportingDisabled").Return(true)
+	options := newRoxOptions(map[string]interface{}{"RoxyURL": "http://site.com"})
 
 	c := core.NewCore()
-	<-c.Setup(sdkSettings, deviceProperties, options)
+	<-c.Setup(newSdkSettings(validApiKey), newDeviceProperties(), options)
 }
 
 func TestCoreWillCheckCoreSetupWhenNoOptions(t *testing.T) {
@@ -157,3 +154,226 @@ func TestEmptyAPIKey(t *testing.T) {
 	<-c.Setup(sdkSettings, deviceProperties, nil)
 	assert.Fail(t, "We should never reach this point because the API key is invalid")
 }
+
+var embeddedConfiguration = `{
+	"data": "{\"application\":\"5008ef002000b62ceaaab37b\",\"targetGroups\":[],\"experiments\":[{\"_id\":\"1\",\"name\":\"embedded\",\"archived\":false,\"featureFlags\":[{\"name\":\"EmbeddedFlag\"}],\"deploymentConfiguration\":{\"condition\":\"true\"}}]}",
+	"signature_v0": "",
+	"signed_date": "2018-01-09T19:02:00.720Z"
+}`
+
+func TestCoreWillApplyEmbeddedConfigurationBeforeFetch(t *testing.T) {
+	var statuses []model.FetcherStatus
+	options := newRoxOptions(map[string]interface{}{
+		"ConfigurationFetchedHandler": func(args *model.ConfigurationFetchedArgs) {
+			statuses = append(statuses, args.FetcherStatus)
+		},
+		"EmbeddedConfiguration": embeddedConfiguration,
+	})
+
+	flag := entities.NewFlag(false)
+	c := core.NewCore()
+	c.Register("", &struct{ EmbeddedFlag model.Flag }{flag})
+	<-c.Setup(newSdkSettings(validApiKey), newDeviceProperties(), options)
+
+	assert.True(t, flag.IsEnabled(nil))
+	assert.Equal(t, model.FetcherStatusAppliedFromEmbedded, statuses[0])
+}
+
+func TestCoreWillApplyConfigurationWithConditionsThatFailToCompile(t *testing.T) {
+	invalidConfiguration := strings.Replace(embeddedConfiguration, `\"targetGroups\":[]`, `\"targetGroups\":[{\"_id\":\"tg\",\"condition\":\"sha256(\\\"x\\\")\"}]`, 1)
+	invalidConfiguration = strings.Replace(invalidConfiguration, `\"experiments\":[`, `\"experiments\":[{\"_id\":\"2\",\"name\":\"invalid\",\"archived\":false,\"featureFlags\":[{\"name\":\"InvalidFlag\"}],\"deploymentConfiguration\":{\"condition\":\"sha256(\\\"x\\\")\"}},`, 1)
+	var evaluationErrors []error
+	options := newRoxOptions(map[string]interface{}{
+		"EmbeddedConfiguration": invalidConfiguration,
+		"EvaluationErrorHandler": func(err error) {
+			evaluationErrors = append(evaluationErrors, err)
+		},
+	})
+
+	flags := &struct {
+		EmbeddedFlag model.Flag
+		InvalidFlag  model.Flag
+	}{entities.NewFlag(false), entities.NewFlag(true)}
+	c := core.NewCore()
+	c.Register("", flags)
+	<-c.Setup(newSdkSettings(validApiKey), newDeviceProperties(), options)
+
+	assert.True(t, flags.EmbeddedFlag.IsEnabled(nil))
+	assert.True(t, flags.InvalidFlag.IsEnabled(nil))
+	var evaluationError *roxx.EvaluationError
+	assert.Len(t, evaluationErrors, 1)
+	assert.True(t, errors.As(evaluationErrors[0], &evaluationError))
+	assert.Equal(t, `sha256("x")`, evaluationError.Expression)
+	// the configuration lock was released
+	status, _ := c.FetchContext(gocontext.Background())
+	assert.Equal(t, model.FetcherStatusErrorFetchedFailed, status)
+}
+
+func TestCoreWillApplyCachedConfigurationBeforeEmbedded(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "rox-cache")
+	defer os.RemoveAll(dir)
+	cache := configuration.NewFileCache(filepath.Join(dir, "configuration.json"))
+	assert.Nil(t, cache.Save(embeddedConfiguration))
+
+	var statuses []model.FetcherStatus
+	options := newRoxOptions(map[string]interface{}{
+		"ConfigurationFetchedHandler": func(args *model.ConfigurationFetchedArgs) {
+			statuses = append(statuses, args.FetcherStatus)
+		},
+		"EmbeddedConfiguration": embeddedConfiguration,
+		"ConfigurationCache":    cache,
+	})
+
+	flag := entities.NewFlag(false)
+	c := core.NewCore()
+	c.Register("", &struct{ EmbeddedFlag model.Flag }{flag})
+	<-c.Setup(newSdkSettings(validApiKey), newDeviceProperties(), options)
+
+	assert.True(t, flag.IsEnabled(nil))
+	assert.Equal(t, model.FetcherStatusAppliedFromLocalStorage, statuses[0])
+	assert.NotContains(t, statuses, model.FetcherStatusAppliedFromEmbedded)
+}
+
+func TestCoreWillApplyConfigurationFromFile(t *testing.T) {
+	for apiKey, application := range map[string]string{
+		validApiKey: "5008ef002000b62ceaaab37b",
+		// offline the API key does not need to be a rollout one
+		"offline-app": "offline-app",
+	} {
+		dir, _ := ioutil.TempDir("", "rox-file")
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "configuration.json")
+		assert.Nil(t, ioutil.WriteFile(path, []byte(strings.Replace(embeddedConfiguration, "5008ef002000b62ceaaab37b", application, 1)), 0600))
+
+		var statuses []model.FetcherStatus
+		options := newRoxOptions(map[string]interface{}{
+			"RoxyURL": "",
+			"ConfigurationFetchedHandler": func(args *model.ConfigurationFetchedArgs) {
+				statuses = append(statuses, args.FetcherStatus)
+			},
+			"ConfigurationFilePath": path,
+		})
+
+		flag := entities.NewFlag(false)
+		c := core.NewCore()
+		c.Register("", &struct{ EmbeddedFlag model.Flag }{flag})
+		<-c.Setup(newSdkSettings(apiKey), newDeviceProperties(), options)
+
+		assert.True(t, flag.IsEnabled(nil), apiKey)
+		assert.Equal(t, []model.FetcherStatus{model.FetcherStatusAppliedFromFile}, statuses, apiKey)
+		<-c.Shutdown()
+	}
+}
+
+func TestCoreFetchContextWillFailBeforeSetup(t *testing.T) {
+	c := core.NewCore()
+	status, err := c.FetchContext(gocontext.Background())
+
+	assert.Equal(t, model.FetcherStatusErrorFetchedFailed, status)
+	assert.NotNil(t, err)
+}
+
+func TestCoreFetchContextWillReturnContextError(t *testing.T) {
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+
+	c := core.NewCore()
+	<-c.SetupContext(ctx, newSdkSettings(validApiKey), newDeviceProperties(), newRoxOptions(nil))
+	status, err := c.FetchContext(ctx)
+
+	assert.Equal(t, model.FetcherStatusErrorFetchedFailed, status)
+	assert.Equal(t, gocontext.Canceled, err)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestCoreWillUseHTTPClientFromOptions(t *testing.T) {
+	var requestedURLs []string
+	httpClient := &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		requestedURLs = append(requestedURLs, request.URL.String())
+		return nil, fmt.Errorf("offline")
+	})}
+	options := newRoxOptions(map[string]interface{}{
+		"RoxyURL":    "http://roxy.local",
+		"HTTPClient": httpClient,
+	})
+
+	c := core.NewCore()
+	<-c.Setup(newSdkSettings(validApiKey), newDeviceProperties(), options)
+
+	assert.Equal(t, 1, len(requestedURLs))
+	assert.Contains(t, requestedURLs[0], "http://roxy.local/")
+}
+
+func TestCoreWillLogToLoggerFromOptions(t *testing.T) {
+	logger := &mocks.Logger{}
+	logger.On("Error", "Failed to parse embedded configuration", nil).Return()
+	logger.On("Debug", mock.Anything, mock.Anything).Return()
+	logger.On("Warn", mock.Anything, mock.Anything).Return()
+	logger.On("Error", mock.Anything, mock.Anything).Return()
+	options := newRoxOptions(map[string]interface{}{
+		"EmbeddedConfiguration": "not a configuration",
+		"Logger":                logger,
+	})
+
+	c := core.NewCore()
+	<-c.Setup(newSdkSettings(validApiKey), newDeviceProperties(), options)
+
+	logger.AssertCalled(t, "Error", "Failed to parse embedded configuration", nil)
+	assert.NotEqual(t, logger, logging.GetLogger())
+}
+
+func newSdkSettings(apiKey string) *mocks.SdkSettings {
+	sdkSettings := &mocks.SdkSettings{}
+	sdkSettings.On("DevModeSecret").Return("")
+	sdkSettings.On("APIKey").Return(apiKey)
+	return sdkSettings
+}
+
+func newDeviceProperties() *mocks.DeviceProperties {
+	deviceProperties := &mocks.DeviceProperties{}
+	deviceProperties.On("GetAllProperties").Return(map[string]string{})
+	deviceProperties.On("DistinctID").Return("")
+	deviceProperties.On("RolloutKey").Return(validApiKey)
+	return deviceProperties
+}
+
+// newRoxOptions mocks every RoxOptions method, overrides replace the values returned by the methods they name.
+// By default the options point to a roxy that can not be reached and disable signature verification and analytics
+func newRoxOptions(overrides map[string]interface{}) *mocks.RoxOptions {
+	values := map[string]interface{}{
+		"RoxyURL":                         "http://127.0.0.1:1",
+		"FetchInterval":                   time.Duration(0),
+		"ConfigurationFetchedHandler":     nil,
+		"ImpressionHandler":               nil,
+		"SelfManagedOptions":              nil,
+		"DynamicPropertyRuleHandler":      nil,
+		"IsSignatureVerificationDisabled": true,
+		"IsAnalyticsReportingDisabled":    true,
+		"EmbeddedConfiguration":           "",
+		"ConfigurationCache":              nil,
+		"ConfigurationFilePath":           "",
+		"HTTPClient":                      nil,
+		"RequestInterceptors":             nil,
+		"ResponseInterceptors":            nil,
+		"Logger":                          nil,
+		"EvaluationErrorHandler":          nil,
+		"IsStrictEvaluationEnabled":       false,
+	}
+	for method, value := range overrides {
+		if _, ok := values[method]; !ok {
+			panic(fmt.Sprintf("RoxOptions has no method %s", method))
+		}
+		values[method] = value
+	}
+
+	options := &mocks.RoxOptions{}
+	for method, value := range values {
+		options.On(method).Return(value)
+	}
+	return options
+}
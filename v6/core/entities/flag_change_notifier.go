@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"sync"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/logging"
+	"github.com/rollout/rox-go/v6/core/model"
+)
+
+type flagSubscription struct {
+	flagName string
+	context  context.Context
+	handler  model.FlagChangedHandler
+	value    string
+	hasValue bool
+	// generation is the Notify call value was evaluated by, older evaluations finishing late are discarded
+	generation uint64
+}
+
+// FlagChangeNotifier re-evaluates watched flags and calls their handlers when the value changes
+type FlagChangeNotifier struct {
+	flagRepository model.FlagRepository
+	subscriptions  []*flagSubscription
+	generation     uint64
+	mutex          sync.Mutex
+	logger         logging.Logger
+}
+
+func NewFlagChangeNotifier(flagRepository model.FlagRepository, logger logging.Logger) *FlagChangeNotifier {
+	return &FlagChangeNotifier{
+		flagRepository: flagRepository,
+		logger:         logging.OrGlobal(logger),
+	}
+}
+
+// Subscribe watches the flag named flagName as evaluated with ctx, the current value is the baseline for later changes
+func (n *FlagChangeNotifier) Subscribe(flagName string, ctx context.Context, handler model.FlagChangedHandler) {
+	subscription := &flagSubscription{
+		flagName: flagName,
+		context:  ctx,
+		handler:  handler,
+	}
+
+	if flag := n.flagRepository.GetFlag(flagName); flag != nil {
+		subscription.value = flag.(model.InternalVariant).EvaluateAsString(ctx)
+		subscription.hasValue = true
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.subscriptions = append(n.subscriptions, subscription)
+}
+
+// Notify re-evaluates all the watched flags and calls the handlers of those whose value changed.
+// Flags are evaluated without holding the lock, so slow custom properties do not block Subscribe or other notifications
+func (n *FlagChangeNotifier) Notify() {
+	type change struct {
+		handler  model.FlagChangedHandler
+		oldValue string
+		newValue string
+	}
+	type evaluation struct {
+		subscription *flagSubscription
+		value        string
+	}
+
+	n.mutex.Lock()
+	n.generation++
+	generation := n.generation
+	subscriptions := make([]*flagSubscription, len(n.subscriptions))
+	copy(subscriptions, n.subscriptions)
+	n.mutex.Unlock()
+
+	evaluations := make([]evaluation, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		flag := n.flagRepository.GetFlag(subscription.flagName)
+		if flag == nil {
+			continue
+		}
+		evaluations = append(evaluations, evaluation{subscription, flag.(model.InternalVariant).EvaluateAsString(subscription.context)})
+	}
+
+	var changes []change
+	n.mutex.Lock()
+	for _, e := range evaluations {
+		subscription := e.subscription
+		if subscription.generation > generation {
+			continue
+		}
+		if subscription.hasValue && subscription.value != e.value {
+			changes = append(changes, change{subscription.handler, subscription.value, e.value})
+		}
+		subscription.value = e.value
+		subscription.hasValue = true
+		subscription.generation = generation
+	}
+	n.mutex.Unlock()
+
+	for _, c := range changes {
+		n.invoke(c.handler, c.oldValue, c.newValue)
+	}
+}
+
+func (n *FlagChangeNotifier) invoke(handler model.FlagChangedHandler, oldValue, newValue string) {
+	defer func() {
+		if r := recover(); r != nil {
+			n.logger.Error("Failed to execute flag changed handler, panic", r)
+		}
+	}()
+
+	handler(oldValue, newValue)
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/repositories"
+	"github.com/rollout/rox-go/v6/core/roxx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagChangeNotifierWillNotifyOnlyWhenValueChanges(t *testing.T) {
+	flagRepo := repositories.NewFlagRepository()
+	expRepo := repositories.NewConfigurationRepository()
+	flagRepo.AddFlag(NewRoxInt(1, []int{2, 3}), "poolSize")
+	flagSetter := NewFlagSetter(flagRepo, roxx.NewParser(), expRepo, nil)
+
+	var changes [][]string
+	notifier := NewFlagChangeNotifier(flagRepo, nil)
+	notifier.Subscribe("poolSize", nil, func(oldValue, newValue string) {
+		changes = append(changes, []string{oldValue, newValue})
+	})
+
+	expRepo.SetExperiments([]*model.ExperimentModel{
+		model.NewExperimentModel("1", "exp", "2", false, []string{"poolSize"}, nil),
+	})
+	flagSetter.SetExperiments()
+	notifier.Notify()
+	notifier.Notify()
+
+	expRepo.SetExperiments(nil)
+	flagSetter.SetExperiments()
+	notifier.Notify()
+
+	assert.Equal(t, [][]string{{"1", "2"}, {"2", "1"}}, changes)
+}
+
+func TestFlagChangeNotifierWillUseFirstValueOfLateFlagAsBaseline(t *testing.T) {
+	flagRepo := repositories.NewFlagRepository()
+
+	numberOfChanges := 0
+	notifier := NewFlagChangeNotifier(flagRepo, nil)
+	notifier.Subscribe("flag", nil, func(oldValue, newValue string) {
+		numberOfChanges++
+	})
+
+	notifier.Notify()
+	flagRepo.AddFlag(NewFlag(true), "flag")
+	notifier.Notify()
+
+	assert.Equal(t, 0, numberOfChanges)
+}
+
+func TestFlagChangeNotifierWillNotSendImpressions(t *testing.T) {
+	flagRepo := repositories.NewFlagRepository()
+	expRepo := repositories.NewConfigurationRepository()
+	flagRepo.AddFlag(NewFlag(false), "flag")
+	impressionInvoker := &countingImpressionInvoker{}
+	flagSetter := NewFlagSetter(flagRepo, roxx.NewParser(), expRepo, impressionInvoker)
+
+	var newValues []string
+	notifier := NewFlagChangeNotifier(flagRepo, nil)
+	notifier.Subscribe("flag", nil, func(oldValue, newValue string) {
+		newValues = append(newValues, newValue)
+	})
+
+	expRepo.SetExperiments([]*model.ExperimentModel{
+		model.NewExperimentModel("1", "exp", "\"true\"", false, []string{"flag"}, nil),
+	})
+	flagSetter.SetExperiments()
+	notifier.Notify()
+
+	assert.Equal(t, []string{"true"}, newValues)
+	assert.Equal(t, 0, impressionInvoker.count)
+}
+
+type countingImpressionInvoker struct {
+	count int
+}
+
+func (i *countingImpressionInvoker) Invoke(value *model.ReportingValue, context context.Context) {
+	i.count++
+}
+
+func (i *countingImpressionInvoker) RegisterImpressionHandler(handler model.ImpressionHandler) {
+}
+
+func TestFlagChangeNotifierWillEvaluateFlagsWithoutHoldingTheLock(t *testing.T) {
+	flagRepo := repositories.NewFlagRepository()
+	expRepo := repositories.NewConfigurationRepository()
+	flagRepo.AddFlag(NewFlag(false), "flag")
+	parser := roxx.NewParser()
+	flagSetter := NewFlagSetter(flagRepo, parser, expRepo, nil)
+	notifier := NewFlagChangeNotifier(flagRepo, nil)
+
+	subscribing := false
+	parser.AddOperator("subscribing", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		if subscribing {
+			subscribing = false
+			notifier.Subscribe("flag", nil, func(oldValue, newValue string) {})
+		}
+		stack.Push(true)
+	})
+	expRepo.SetExperiments([]*model.ExperimentModel{
+		model.NewExperimentModel("1", "exp", "subscribing()", false, []string{"flag"}, nil),
+	})
+	flagSetter.SetExperiments()
+
+	numberOfChanges := 0
+	notifier.Subscribe("flag", nil, func(oldValue, newValue string) {
+		numberOfChanges++
+	})
+	subscribing = true
+	notifier.Notify()
+
+	assert.Equal(t, 0, numberOfChanges)
+	assert.Equal(t, 2, len(notifier.subscriptions))
+}
@@ -3,51 +3,38 @@ package entities
 import (
 	"github.com/rollout/rox-go/v6/core/model"
 	"github.com/rollout/rox-go/v6/core/roxx"
-	"github.com/rollout/rox-go/v6/core/utils"
 )
 
 type FlagSetter struct {
-	flagRepository       model.FlagRepository
-	parser               roxx.Parser
-	experimentRepository model.ExperimentRepository
-	impressionInvoker    model.ImpressionInvoker
+	flagRepository          model.FlagRepository
+	parser                  roxx.Parser
+	configurationRepository model.ConfigurationRepository
+	impressionInvoker       model.ImpressionInvoker
 }
 
-func NewFlagSetter(flagRepository model.FlagRepository, parser roxx.Parser, experimentRepository model.ExperimentRepository, impressionInvoker model.ImpressionInvoker) *FlagSetter {
+func NewFlagSetter(flagRepository model.FlagRepository, parser roxx.Parser, configurationRepository model.ConfigurationRepository, impressionInvoker model.ImpressionInvoker) *FlagSetter {
 	fs := &FlagSetter{
-		flagRepository:       flagRepository,
-		parser:               parser,
-		experimentRepository: experimentRepository,
-		impressionInvoker:    impressionInvoker,
+		flagRepository:          flagRepository,
+		parser:                  parser,
+		configurationRepository: configurationRepository,
+		impressionInvoker:       impressionInvoker,
 	}
 
 	fs.flagRepository.RegisterFlagAddedHandler(func(variant model.Variant) {
-		exp := fs.experimentRepository.GetExperimentByFlag(variant.Name())
-		fs.setFlagData(variant, exp)
+		fs.setFlagData(variant, fs.configurationRepository.Snapshot())
 	})
 
 	return fs
 }
 
+// SetExperiments sets every flag for evaluation with the applied configuration
 func (fs *FlagSetter) SetExperiments() {
-	var flagsWithCondition []string
-	for _, exp := range fs.experimentRepository.GetAllExperiments() {
-		for _, flagName := range exp.Flags {
-			flag := fs.flagRepository.GetFlag(flagName)
-			if flag != nil {
-				fs.setFlagData(flag, exp)
-				flagsWithCondition = append(flagsWithCondition, flagName)
-			}
-		}
-	}
-
+	configuration := fs.configurationRepository.Snapshot()
 	for _, flag := range fs.flagRepository.GetAllFlags() {
-		if !utils.ContainsString(flagsWithCondition, flag.Name()) {
-			fs.setFlagData(flag, nil)
-		}
+		fs.setFlagData(flag, configuration)
 	}
 }
 
-func (fs *FlagSetter) setFlagData(variant model.Variant, experiment *model.ExperimentModel) {
-	variant.(model.InternalVariant).SetForEvaluation(fs.parser, experiment, fs.impressionInvoker)
+func (fs *FlagSetter) setFlagData(variant model.Variant, configuration model.ConfigurationSnapshot) {
+	variant.(model.InternalVariant).SetForConfiguration(fs.parser, configuration, fs.impressionInvoker)
 }
@@ -14,7 +14,7 @@ import (
 
 func TestFlagSetterWillSetFlagData(t *testing.T) {
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 
 	parser := &mocks.Parser{}
 	parser.On("EvaluateExpression", mock.Anything, mock.Anything).Return(roxx.NewEvaluationResult(nil))
@@ -44,7 +44,7 @@ func TestFlagSetterWillSetFlagData(t *testing.T) {
 
 func TestFlagSetterWillNotSetForOtherFlag(t *testing.T) {
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 
 	parser := &mocks.Parser{}
 	parser.On("EvaluateExpression", mock.Anything, mock.Anything).Return(roxx.NewEvaluationResult(nil))
@@ -75,7 +75,7 @@ func TestFlagSetterWillNotSetForOtherFlag(t *testing.T) {
 
 func TestFlagSetterWillSetExperimentForFlagAndWillRemoveIt(t *testing.T) {
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 
 	parser := &mocks.Parser{}
 	parser.On("EvaluateExpression", mock.Anything, mock.Anything).Return(roxx.NewEvaluationResult(nil))
@@ -113,7 +113,7 @@ func TestFlagSetterWillSetExperimentForFlagAndWillRemoveIt(t *testing.T) {
 
 func TestFlagSetterWillSetFlagWithoutExperimentAndThenAddExperiment(t *testing.T) {
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 
 	parser := &mocks.Parser{}
 	parser.On("EvaluateExpression", mock.Anything, mock.Anything).Return(roxx.NewEvaluationResult(nil))
@@ -149,7 +149,7 @@ func TestFlagSetterWillSetFlagWithoutExperimentAndThenAddExperiment(t *testing.T
 
 func TestFlagSetterWillSetDataForAddedFlag(t *testing.T) {
 	flagRepo := repositories.NewFlagRepository()
-	expRepo := repositories.NewExperimentRepository()
+	expRepo := repositories.NewConfigurationRepository()
 
 	parser := &mocks.Parser{}
 	parser.On("EvaluateExpression", mock.Anything, mock.Anything).Return(roxx.NewEvaluationResult(nil))
@@ -181,3 +181,28 @@ func TestFlagSetterWillSetDataForAddedFlag(t *testing.T) {
 	assert.Equal(t, "1", flagRepo.GetFlag("f1").(internalVariant).ClientExperiment().Identifier)
 	assert.Nil(t, flagRepo.GetFlag("f2").(internalVariant).ClientExperiment())
 }
+
+func TestFlagSetterCanSetExperimentsWhileFlagsAreEvaluated(t *testing.T) {
+	flagRepo := repositories.NewFlagRepository()
+	expRepo := repositories.NewConfigurationRepository()
+	flag := NewRoxString("1", []string{"2", "3"})
+	flagRepo.AddFlag(flag, "f1")
+	flagSetter := NewFlagSetter(flagRepo, roxx.NewParser(), expRepo, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			expRepo.SetExperiments([]*model.ExperimentModel{
+				model.NewExperimentModel("1", "1", "\"2\"", false, []string{"f1"}, nil),
+			})
+			flagSetter.SetExperiments()
+			flag.(model.InternalVariant).SetContext(nil)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		assert.Contains(t, []string{"1", "2"}, flag.GetValue(nil))
+	}
+	<-done
+}
@@ -12,13 +12,8 @@ import (
 
 type roxDouble struct {
 	roxVariant
-	defaultValue      float64
-	options           []float64
-	condition         string
-	parser            roxx.Parser
-	globalContext     context.Context
-	impressionInvoker model.ImpressionInvoker
-	clientExperiment  *model.Experiment
+	defaultValue float64
+	options      []float64
 }
 
 func NewRoxDouble(defaultValue float64, options []float64) model.RoxDouble {
@@ -64,27 +59,6 @@ func (v *roxDouble) Options() []float64 {
 	return v.options
 }
 
-func (v *roxDouble) SetForEvaluation(parser roxx.Parser, experiment *model.ExperimentModel, impressionInvoker model.ImpressionInvoker) {
-	if experiment != nil {
-		v.clientExperiment = model.NewExperiment(experiment)
-		v.condition = experiment.Condition
-	} else {
-		v.clientExperiment = nil
-		v.condition = ""
-	}
-
-	v.parser = parser
-	v.impressionInvoker = impressionInvoker
-}
-
-func (v *roxDouble) SetContext(globalContext context.Context) {
-	v.globalContext = globalContext
-}
-
-func (v *roxDouble) SetName(name string) {
-	v.name = name
-}
-
 func (v *roxDouble) GetValueAsString(ctx context.Context) string {
 	return strconv.FormatFloat(v.GetValue(ctx), 'f', -1, 64)
 }
@@ -95,42 +69,44 @@ func (v *roxDouble) GetValue(ctx context.Context) float64 {
 }
 
 func (v *roxDouble) InternalGetValue(ctx context.Context) (returnValue float64, isDefault bool) {
-	returnValue, isDefault = v.defaultValue, true
-	mergedContext := context.NewMergedContext(v.globalContext, ctx)
-	sendImpression := false
-
-	if v.parser != nil && v.condition != "" {
-		evaluationResult := v.parser.EvaluateExpression(v.condition, mergedContext)
-		value, err := evaluationResult.DoubleValue()
-		if err == nil {
-			returnValue, isDefault = value, false
-			sendImpression = true
-		}
-	}
+	data, mergedContext := v.evaluation(ctx)
+	returnValue, isDefault = v.evaluate(data, mergedContext)
 
-	if v.impressionInvoker != nil && sendImpression {
+	if data.impressionInvoker != nil && !isDefault {
 		targeting := false
-		if v.clientExperiment != nil {
+		if data.clientExperiment != nil {
 			targeting = true
 		}
-		v.impressionInvoker.Invoke(model.NewReportingValue(v.name, strconv.FormatFloat(returnValue, 'f', -1, 64), targeting), mergedContext)
+
+		data.impressionInvoker.Invoke(model.NewReportingValue(v.name, strconv.FormatFloat(returnValue, 'f', -1, 64), targeting), mergedContext)
 	}
 
 	return returnValue, isDefault
 }
 
-func (v *roxDouble) Condition() string {
-	return v.condition
+func (v *roxDouble) EvaluateAsString(ctx context.Context) string {
+	returnValue, _ := v.evaluate(v.evaluation(ctx))
+	return strconv.FormatFloat(returnValue, 'f', -1, 64)
 }
 
-func (v *roxDouble) Parser() roxx.Parser {
-	return v.parser
+func (v *roxDouble) evaluate(data *evaluationData, mergedContext context.Context) (returnValue float64, isDefault bool) {
+	if data.parser != nil && data.condition != "" {
+		return v.valueFromResult(data.parser.EvaluateCompiledExpression(data.compiledCondition, mergedContext))
+	}
+	return v.defaultValue, true
 }
 
-func (v *roxDouble) ImpressionInvoker() model.ImpressionInvoker {
-	return v.impressionInvoker
+func (v *roxDouble) valueFromResult(evaluationResult roxx.EvaluationResult) (returnValue float64, isDefault bool) {
+	value, err := evaluationResult.DoubleValue()
+	if err != nil {
+		return v.defaultValue, true
+	}
+	return value, false
 }
 
-func (v *roxDouble) ClientExperiment() *model.Experiment {
-	return v.clientExperiment
+func (v *roxDouble) Explain(ctx context.Context) *model.FlagExplanation {
+	return v.explain(ctx, v.GetDefaultAsString(), func(evaluationResult roxx.EvaluationResult) (string, bool) {
+		value, isDefault := v.valueFromResult(evaluationResult)
+		return strconv.FormatFloat(value, 'f', -1, 64), isDefault
+	})
 }
@@ -12,13 +12,8 @@ import (
 
 type roxInt struct {
 	roxVariant
-	defaultValue      int
-	options           []int
-	condition         string
-	parser            roxx.Parser
-	globalContext     context.Context
-	impressionInvoker model.ImpressionInvoker
-	clientExperiment  *model.Experiment
+	defaultValue int
+	options      []int
 }
 
 func NewRoxInt(defaultValue int, options []int) model.RoxInt {
@@ -64,27 +59,6 @@ func (v *roxInt) Options() []int {
 	return v.options
 }
 
-func (v *roxInt) SetForEvaluation(parser roxx.Parser, experiment *model.ExperimentModel, impressionInvoker model.ImpressionInvoker) {
-	if experiment != nil {
-		v.clientExperiment = model.NewExperiment(experiment)
-		v.condition = experiment.Condition
-	} else {
-		v.clientExperiment = nil
-		v.condition = ""
-	}
-
-	v.parser = parser
-	v.impressionInvoker = impressionInvoker
-}
-
-func (v *roxInt) SetContext(globalContext context.Context) {
-	v.globalContext = globalContext
-}
-
-func (v *roxInt) SetName(name string) {
-	v.name = name
-}
-
 func (v *roxInt) GetValueAsString(ctx context.Context) string {
 	return strconv.Itoa(v.GetValue(ctx))
 }
@@ -95,44 +69,44 @@ func (v *roxInt) GetValue(ctx context.Context) int {
 }
 
 func (v *roxInt) InternalGetValue(ctx context.Context) (returnValue int, isDefault bool) {
-	returnValue, isDefault = v.defaultValue, true
-	mergedContext := context.NewMergedContext(v.globalContext, ctx)
-	sendImpression := false
-
-	if v.parser != nil && v.condition != "" {
-		evaluationResult := v.parser.EvaluateExpression(v.condition, mergedContext)
-		value, err := evaluationResult.IntValue()
-		if err == nil {
-			returnValue, isDefault = value, false
-			sendImpression = true
-		}
-	}
-
-	if v.impressionInvoker != nil && sendImpression {
+	data, mergedContext := v.evaluation(ctx)
+	returnValue, isDefault = v.evaluate(data, mergedContext)
 
+	if data.impressionInvoker != nil && !isDefault {
 		targeting := false
-		if v.clientExperiment != nil {
+		if data.clientExperiment != nil {
 			targeting = true
 		}
 
-		v.impressionInvoker.Invoke(model.NewReportingValue(v.name, strconv.Itoa(returnValue), targeting), mergedContext)
+		data.impressionInvoker.Invoke(model.NewReportingValue(v.name, strconv.Itoa(returnValue), targeting), mergedContext)
 	}
 
 	return returnValue, isDefault
 }
 
-func (v *roxInt) Condition() string {
-	return v.condition
+func (v *roxInt) EvaluateAsString(ctx context.Context) string {
+	returnValue, _ := v.evaluate(v.evaluation(ctx))
+	return strconv.Itoa(returnValue)
 }
 
-func (v *roxInt) Parser() roxx.Parser {
-	return v.parser
+func (v *roxInt) evaluate(data *evaluationData, mergedContext context.Context) (returnValue int, isDefault bool) {
+	if data.parser != nil && data.condition != "" {
+		return v.valueFromResult(data.parser.EvaluateCompiledExpression(data.compiledCondition, mergedContext))
+	}
+	return v.defaultValue, true
 }
 
-func (v *roxInt) ImpressionInvoker() model.ImpressionInvoker {
-	return v.impressionInvoker
+func (v *roxInt) valueFromResult(evaluationResult roxx.EvaluationResult) (returnValue int, isDefault bool) {
+	value, err := evaluationResult.IntValue()
+	if err != nil {
+		return v.defaultValue, true
+	}
+	return value, false
 }
 
-func (v *roxInt) ClientExperiment() *model.Experiment {
-	return v.clientExperiment
+func (v *roxInt) Explain(ctx context.Context) *model.FlagExplanation {
+	return v.explain(ctx, v.GetDefaultAsString(), func(evaluationResult roxx.EvaluationResult) (string, bool) {
+		value, isDefault := v.valueFromResult(evaluationResult)
+		return strconv.Itoa(value), isDefault
+	})
 }
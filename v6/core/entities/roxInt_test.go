@@ -164,3 +164,24 @@ func TestRoxIntForConsistencyWithBoolean(t *testing.T) {
 	assert.Equal(t, 1, roxInt.GetValue(nil))
 	assert.False(t, isImpressionRaised)
 }
+
+func TestRoxIntWillExplainValue(t *testing.T) {
+	roxInt := NewRoxInt(1, []int{2, 3})
+	roxInt.(model.InternalVariant).SetName("poolSize")
+
+	explanation := roxInt.(model.InternalVariant).Explain(nil)
+	assert.Equal(t, "poolSize", explanation.FlagName)
+	assert.Equal(t, "1", explanation.Value)
+	assert.True(t, explanation.IsDefault)
+	assert.Nil(t, explanation.Trace)
+
+	roxInt.(model.InternalVariant).SetForEvaluation(roxx.NewParser(), model.NewExperimentModel("id", "name", `ifThen(true, 3, 2)`, false, []string{"poolSize"}, nil), nil)
+
+	explanation = roxInt.(model.InternalVariant).Explain(nil)
+	assert.Equal(t, "3", explanation.Value)
+	assert.False(t, explanation.IsDefault)
+	assert.Equal(t, "id", explanation.Experiment.Identifier)
+	assert.Equal(t, `ifThen(true, 3, 2)`, explanation.Condition)
+	assert.Equal(t, "ifThen", explanation.Trace.Steps[0].Operator)
+	assert.Equal(t, 3, explanation.Trace.Result)
+}
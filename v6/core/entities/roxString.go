@@ -17,13 +17,8 @@ type internalVariant interface {
 
 type roxString struct {
 	roxVariant
-	defaultValue      string
-	options           []string
-	condition         string
-	parser            roxx.Parser
-	globalContext     context.Context
-	impressionInvoker model.ImpressionInvoker
-	clientExperiment  *model.Experiment
+	defaultValue string
+	options      []string
 }
 
 func NewRoxString(defaultValue string, options []string) model.RoxString {
@@ -63,27 +58,6 @@ func (v *roxString) Options() []string {
 	return v.options
 }
 
-func (v *roxString) SetForEvaluation(parser roxx.Parser, experiment *model.ExperimentModel, impressionInvoker model.ImpressionInvoker) {
-	if experiment != nil {
-		v.clientExperiment = model.NewExperiment(experiment)
-		v.condition = experiment.Condition
-	} else {
-		v.clientExperiment = nil
-		v.condition = ""
-	}
-
-	v.parser = parser
-	v.impressionInvoker = impressionInvoker
-}
-
-func (v *roxString) SetContext(globalContext context.Context) {
-	v.globalContext = globalContext
-}
-
-func (v *roxString) SetName(name string) {
-	v.name = name
-}
-
 func (v *roxString) GetValueAsString(ctx context.Context) string {
 	return v.GetValue(ctx)
 }
@@ -94,53 +68,53 @@ func (v *roxString) GetValue(ctx context.Context) string {
 }
 
 func (v *roxString) InternalGetValue(ctx context.Context) (returnValue string, isDefault bool) {
-	returnValue, isDefault = v.defaultValue, true
-	mergedContext := context.NewMergedContext(v.globalContext, ctx)
-	sendImpression := false
-
-	if v.parser != nil && v.condition != "" {
-		evaluationResult := v.parser.EvaluateExpression(v.condition, mergedContext)
-		value := evaluationResult.StringValue()
-		if value != "" {
-			switch v.FlagType() {
-			case consts.StringType:
-				if _, ok := evaluationResult.Value().(string); ok {
-					returnValue, isDefault = value, false
-					sendImpression = true
-				}
-			case consts.BoolType:
-				if value == roxx.FlagFalseValue || value == roxx.FlagTrueValue {
-					returnValue, isDefault = value, false
-					sendImpression = true
-				}
-			}
-		}
-	}
+	data, mergedContext := v.evaluation(ctx)
+	returnValue, isDefault = v.evaluate(data, mergedContext)
 
-	if v.impressionInvoker != nil && sendImpression {
+	if data.impressionInvoker != nil && !isDefault {
 		targeting := false
-		if v.clientExperiment != nil {
+		if data.clientExperiment != nil {
 			targeting = true
 		}
 
-		v.impressionInvoker.Invoke(model.NewReportingValue(v.name, returnValue, targeting), mergedContext)
+		data.impressionInvoker.Invoke(model.NewReportingValue(v.name, returnValue, targeting), mergedContext)
 	}
 
 	return returnValue, isDefault
 }
 
-func (v *roxString) Condition() string {
-	return v.condition
+func (v *roxString) EvaluateAsString(ctx context.Context) string {
+	returnValue, _ := v.evaluate(v.evaluation(ctx))
+	return returnValue
 }
 
-func (v *roxString) Parser() roxx.Parser {
-	return v.parser
+func (v *roxString) evaluate(data *evaluationData, mergedContext context.Context) (returnValue string, isDefault bool) {
+	if data.parser != nil && data.condition != "" {
+		return v.valueFromResult(data.parser.EvaluateCompiledExpression(data.compiledCondition, mergedContext))
+	}
+	return v.defaultValue, true
 }
 
-func (v *roxString) ImpressionInvoker() model.ImpressionInvoker {
-	return v.impressionInvoker
+func (v *roxString) valueFromResult(evaluationResult roxx.EvaluationResult) (returnValue string, isDefault bool) {
+	returnValue, isDefault = v.defaultValue, true
+
+	value := evaluationResult.StringValue()
+	if value != "" {
+		switch v.FlagType() {
+		case consts.StringType:
+			if _, ok := evaluationResult.Value().(string); ok {
+				returnValue, isDefault = value, false
+			}
+		case consts.BoolType:
+			if value == roxx.FlagFalseValue || value == roxx.FlagTrueValue {
+				returnValue, isDefault = value, false
+			}
+		}
+	}
+
+	return returnValue, isDefault
 }
 
-func (v *roxString) ClientExperiment() *model.Experiment {
-	return v.clientExperiment
+func (v *roxString) Explain(ctx context.Context) *model.FlagExplanation {
+	return v.explain(ctx, v.defaultValue, v.valueFromResult)
 }
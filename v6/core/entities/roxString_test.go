@@ -3,6 +3,7 @@ package entities
 import (
 	"testing"
 
+	"github.com/rollout/rox-go/v6/core/context"
 	"github.com/rollout/rox-go/v6/core/impression"
 	"github.com/rollout/rox-go/v6/core/mocks"
 	"github.com/rollout/rox-go/v6/core/model"
@@ -164,3 +165,31 @@ func TestRoxStringForConsistencyWithBool(t *testing.T) {
 	assert.Equal(t, "a", roxString.GetValue(nil))
 	assert.False(t, isImpressionRaised)
 }
+
+type countingParser struct {
+	roxx.Parser
+	compilations int
+	evaluations  int
+}
+
+func (p *countingParser) CompileExpression(expression string) *roxx.CompiledExpression {
+	p.compilations++
+	return p.Parser.CompileExpression(expression)
+}
+
+func (p *countingParser) EvaluateExpression(expression string, context context.Context) roxx.EvaluationResult {
+	p.evaluations++
+	return p.Parser.EvaluateExpression(expression, context)
+}
+
+func TestRoxStringWillCompileConditionOnce(t *testing.T) {
+	parser := &countingParser{Parser: roxx.NewParser()}
+	roxString := NewRoxString("1", []string{"2", "3"})
+	roxString.(model.InternalVariant).SetForEvaluation(parser, model.NewExperimentModel("id", "name", `"2"`, false, nil, nil), nil)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "2", roxString.GetValue(nil))
+	}
+	assert.Equal(t, 1, parser.compilations)
+	assert.Equal(t, 0, parser.evaluations)
+}
@@ -1,8 +1,51 @@
 package entities
 
+import (
+	"sync/atomic"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/roxx"
+)
+
+// evaluationData is never modified once stored, a new configuration replaces it as a whole
+// so that concurrent evaluations see either the previous or the new experiment
+type evaluationData struct {
+	condition         string
+	compiledCondition *roxx.CompiledExpression
+	parser            roxx.Parser
+	impressionInvoker model.ImpressionInvoker
+	clientExperiment  *model.Experiment
+	// configuration is the configuration the experiment was taken from, if any
+	configuration model.ConfigurationSnapshot
+}
+
+func newEvaluationData(parser roxx.Parser, experiment *model.ExperimentModel, impressionInvoker model.ImpressionInvoker, configuration model.ConfigurationSnapshot) *evaluationData {
+	data := &evaluationData{
+		parser:            parser,
+		impressionInvoker: impressionInvoker,
+		configuration:     configuration,
+	}
+	if experiment != nil {
+		data.clientExperiment = model.NewExperiment(experiment)
+		data.condition = experiment.Condition
+	}
+	// conditions are compiled once per configuration rather than looked up in the parser's cache on every evaluation
+	if parser != nil && data.condition != "" {
+		data.compiledCondition = parser.CompileExpression(data.condition)
+	}
+	return data
+}
+
+type globalContextHolder struct {
+	context context.Context
+}
+
 type roxVariant struct {
-	name     string
-	flagType int
+	name           string
+	flagType       int
+	evaluationData atomic.Value
+	globalContext  atomic.Value
 }
 
 func (v *roxVariant) Name() string {
@@ -12,3 +55,87 @@ func (v *roxVariant) Name() string {
 func (v *roxVariant) FlagType() int {
 	return v.flagType
 }
+
+func (v *roxVariant) SetName(name string) {
+	v.name = name
+}
+
+func (v *roxVariant) SetForEvaluation(parser roxx.Parser, experiment *model.ExperimentModel, impressionInvoker model.ImpressionInvoker) {
+	v.evaluationData.Store(newEvaluationData(parser, experiment, impressionInvoker, nil))
+}
+
+func (v *roxVariant) SetForConfiguration(parser roxx.Parser, configuration model.ConfigurationSnapshot, impressionInvoker model.ImpressionInvoker) {
+	v.evaluationData.Store(newEvaluationData(parser, configuration.GetExperimentByFlag(v.name), impressionInvoker, configuration))
+}
+
+func (v *roxVariant) SetContext(globalContext context.Context) {
+	v.globalContext.Store(globalContextHolder{globalContext})
+}
+
+func (v *roxVariant) loadEvaluationData() *evaluationData {
+	if data, ok := v.evaluationData.Load().(*evaluationData); ok {
+		return data
+	}
+	return &evaluationData{}
+}
+
+func (v *roxVariant) mergeContext(ctx context.Context) context.Context {
+	holder, _ := v.globalContext.Load().(globalContextHolder)
+	return context.NewMergedContext(holder.context, ctx)
+}
+
+// evaluation returns the data the variant is evaluated with for ctx, and the context to evaluate its condition with.
+// The context carries the configuration of the data, a variant evaluated by the condition of another flag uses the
+// configuration of that flag instead, even when a newer one has been applied to the variant meanwhile
+func (v *roxVariant) evaluation(ctx context.Context) (*evaluationData, context.Context) {
+	data := v.loadEvaluationData()
+	mergedContext := v.mergeContext(ctx)
+
+	configuration := model.ConfigurationFromContext(mergedContext)
+	if configuration == nil {
+		if data.configuration != nil {
+			mergedContext = model.ContextWithConfiguration(mergedContext, data.configuration)
+		}
+		return data, mergedContext
+	}
+
+	if data.configuration != nil && data.configuration != configuration {
+		data = newEvaluationData(data.parser, configuration.GetExperimentByFlag(v.name), data.impressionInvoker, configuration)
+	}
+	return data, mergedContext
+}
+
+func (v *roxVariant) Condition() string {
+	return v.loadEvaluationData().condition
+}
+
+func (v *roxVariant) Parser() roxx.Parser {
+	return v.loadEvaluationData().parser
+}
+
+func (v *roxVariant) ImpressionInvoker() model.ImpressionInvoker {
+	return v.loadEvaluationData().impressionInvoker
+}
+
+func (v *roxVariant) ClientExperiment() *model.Experiment {
+	return v.loadEvaluationData().clientExperiment
+}
+
+// explain evaluates the variant like GetValueAsString does, recording a trace of the condition evaluation
+func (v *roxVariant) explain(ctx context.Context, defaultValue string, valueFromResult func(roxx.EvaluationResult) (string, bool)) *model.FlagExplanation {
+	data, evaluationContext := v.evaluation(ctx)
+	explanation := &model.FlagExplanation{
+		FlagName:   v.name,
+		Experiment: data.clientExperiment,
+		Condition:  data.condition,
+		Value:      defaultValue,
+		IsDefault:  true,
+	}
+
+	if data.parser != nil && data.condition != "" {
+		var evaluationResult roxx.EvaluationResult
+		evaluationResult, explanation.Trace = data.parser.ExplainExpression(data.condition, evaluationContext)
+		explanation.Value, explanation.IsDefault = valueFromResult(evaluationResult)
+	}
+	return explanation
+}
@@ -28,13 +28,13 @@ func NewExperimentsExtensions(parser roxx.Parser, targetGroupsRepository model.T
 }
 
 func (e *ExperimentsExtensions) Extend() {
-	e.parser.AddOperator("mergeSeed", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("mergeSeed", roxx.NewOperatorSignature(roxx.OperandTypeString, roxx.OperandTypeString, roxx.OperandTypeString), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
 		seed1 := stack.Pop().(string)
 		seed2 := stack.Pop().(string)
 		stack.Push(fmt.Sprintf("%s.%s", seed1, seed2))
 	})
 
-	e.parser.AddOperator("isInPercentage", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("isInPercentage", roxx.NewOperatorSignature(roxx.OperandTypeBoolean, roxx.OperandTypeNumber, roxx.OperandTypeString), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
 		percentage, ok := utils.ToFloat(stack.Pop())
 		seed := stack.Pop().(string)
 
@@ -43,10 +43,11 @@ func (e *ExperimentsExtensions) Extend() {
 		}
 
 		bucket := e.GetBucket(seed)
+		roxx.AddTraceDetail(p, "bucket", bucket)
 		stack.Push(bucket <= percentage)
 	})
 
-	e.parser.AddOperator("isInPercentageRange", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("isInPercentageRange", roxx.NewOperatorSignature(roxx.OperandTypeBoolean, roxx.OperandTypeNumber, roxx.OperandTypeNumber, roxx.OperandTypeString), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
 		percentageLow, ok1 := utils.ToFloat(stack.Pop())
 		percentageHigh, ok2 := utils.ToFloat(stack.Pop())
 		seed := stack.Pop().(string)
@@ -56,20 +57,22 @@ func (e *ExperimentsExtensions) Extend() {
 		}
 
 		bucket := e.GetBucket(seed)
+		roxx.AddTraceDetail(p, "bucket", bucket)
 		stack.Push(percentageLow <= bucket && bucket < percentageHigh)
 	})
 
-	e.parser.AddOperator("flagValue", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("flagValue", roxx.NewOperatorSignature(roxx.OperandTypeString, roxx.OperandTypeString), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
 		featureFlagIdentifier := stack.Pop().(string)
+		defer roxx.EnterDependency(context, "flag "+featureFlagIdentifier)()
 
 		result := roxx.FlagFalseValue
 		variant := e.flagsRepository.GetFlag(featureFlagIdentifier)
 		if variant != nil {
 			result = variant.GetValueAsString(context)
 		} else {
-			flagsExperiment := e.experimentRepository.GetExperimentByFlag(featureFlagIdentifier)
+			flagsExperiment := e.getExperimentByFlag(context, featureFlagIdentifier)
 			if flagsExperiment != nil && flagsExperiment.Condition != "" {
-				experimentEvalResult := e.parser.EvaluateExpression(flagsExperiment.Condition, context).StringValue()
+				experimentEvalResult := p.EvaluateExpression(flagsExperiment.Condition, context).StringValue()
 				if experimentEvalResult != "" {
 					result = experimentEvalResult
 				}
@@ -79,19 +82,35 @@ func (e *ExperimentsExtensions) Extend() {
 		stack.Push(result)
 	})
 
-	e.parser.AddOperator("isInTargetGroup", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("isInTargetGroup", roxx.NewOperatorSignature(roxx.OperandTypeBoolean, roxx.OperandTypeString), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
 		targetGroupIdentifier := stack.Pop().(string)
 
-		targetGroup := e.targetGroupsRepository.GetTargetGroup(targetGroupIdentifier)
+		targetGroup := e.getTargetGroup(context, targetGroupIdentifier)
 		if targetGroup == nil {
 			stack.Push(false)
 		} else {
-			isInTargetGroup := e.parser.EvaluateExpression(targetGroup.Condition, context).BoolValue()
+			defer roxx.EnterDependency(context, "target group "+targetGroupIdentifier)()
+			isInTargetGroup := p.EvaluateExpression(targetGroup.Condition, context).BoolValue()
 			stack.Push(isInTargetGroup)
 		}
 	})
 }
 
+// getTargetGroup looks the target group up in the configuration of the running evaluation, if it carries one
+func (e *ExperimentsExtensions) getTargetGroup(ctx context.Context, id string) *model.TargetGroupModel {
+	if configuration := model.ConfigurationFromContext(ctx); configuration != nil {
+		return configuration.GetTargetGroup(id)
+	}
+	return e.targetGroupsRepository.GetTargetGroup(id)
+}
+
+func (e *ExperimentsExtensions) getExperimentByFlag(ctx context.Context, flagName string) *model.ExperimentModel {
+	if configuration := model.ConfigurationFromContext(ctx); configuration != nil {
+		return configuration.GetExperimentByFlag(flagName)
+	}
+	return e.experimentRepository.GetExperimentByFlag(flagName)
+}
+
 func (e *ExperimentsExtensions) GetBucket(seed string) float64 {
 	hasher := md5.New()
 	hasher.Write([]byte(seed))
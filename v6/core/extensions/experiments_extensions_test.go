@@ -1,6 +1,8 @@
 package extensions_test
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/rollout/rox-go/v6/core/context"
@@ -51,6 +53,18 @@ func TestExperimentsExtensionsGetBucket(t *testing.T) {
 	assert.Equal(t, 0.18721251450181298, bucket)
 }
 
+func TestExperimentsExtensionsInfixPercentageWillIncludeTheBound(t *testing.T) {
+	parser := roxx.NewParser()
+	targetGroupsRepository := repositories.NewTargetGroupRepository()
+	experimentsExtensions := extensions.NewExperimentsExtensions(parser, targetGroupsRepository, nil, nil)
+	experimentsExtensions.Extend()
+
+	expression, err := roxx.CompileInfix(parser, `percentage("device2.seed2") <= 0.18721251450181298`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, true, parser.EvaluateExpression(expression, nil).Value())
+}
+
 func TestExperimentsExtensionsFlagValueNoFlagNoExperiment(t *testing.T) {
 	parser := roxx.NewParser()
 	targetGroupsRepository := repositories.NewTargetGroupRepository()
@@ -172,7 +186,7 @@ func TestExperimentsExtensionsFlagDependency2LevelsBottomNotExists(t *testing.T)
 func TestExperimentsExtensionsFlagDependencyUnexistingFlagButExistingExperiment(t *testing.T) {
 	parser := roxx.NewParser()
 	targetGroupsRepository := repositories.NewTargetGroupRepository()
-	experimentRepository := repositories.NewExperimentRepository()
+	experimentRepository := repositories.NewConfigurationRepository()
 	flagRepository := repositories.NewFlagRepository()
 
 	experimentModels := []*model.ExperimentModel{
@@ -197,7 +211,7 @@ func TestExperimentsExtensionsFlagDependencyUnexistingFlagButExistingExperiment(
 func TestExperimentsExtensionsFlagDependencyUnexistingFlagAndExperimentUndefined(t *testing.T) {
 	parser := roxx.NewParser()
 	targetGroupsRepository := repositories.NewTargetGroupRepository()
-	experimentRepository := repositories.NewExperimentRepository()
+	experimentRepository := repositories.NewConfigurationRepository()
 	flagRepository := repositories.NewFlagRepository()
 
 	experimentModels := []*model.ExperimentModel{
@@ -310,3 +324,271 @@ func TestExperimentsExtensionsFlagDependencyWithContext2LevelMidLevelNoFlagEvalE
 
 	assert.Equal(t, "true", flagValue)
 }
+
+func TestExperimentsExtensionsExplainWillTraceTargetGroupsAndBuckets(t *testing.T) {
+	parser := roxx.NewParser()
+	targetGroupsRepository := repositories.NewTargetGroupRepository()
+	targetGroupsRepository.SetTargetGroups([]*model.TargetGroupModel{
+		model.NewTargetGroupModel("targetGroup1", `isInPercentage(0.5, "device2.seed2")`),
+	})
+	experimentsExtensions := extensions.NewExperimentsExtensions(parser, targetGroupsRepository, nil, nil)
+	experimentsExtensions.Extend()
+
+	result, trace := parser.ExplainExpression(`and(true, isInTargetGroup("targetGroup1"))`, nil)
+
+	assert.Equal(t, true, result.Value())
+	assert.Equal(t, true, trace.Result)
+	assert.Equal(t, 3, len(trace.Steps))
+
+	// and is short-circuit, its first operand is checked before the second one is evaluated
+	andFirst := trace.Steps[0]
+	assert.Equal(t, "and", andFirst.Operator)
+	assert.Equal(t, []interface{}{true}, andFirst.Operands)
+	assert.Empty(t, andFirst.Results)
+
+	isInTargetGroup := trace.Steps[1]
+	assert.Equal(t, "isInTargetGroup", isInTargetGroup.Operator)
+	assert.Equal(t, []interface{}{"targetGroup1"}, isInTargetGroup.Operands)
+	assert.Equal(t, []interface{}{true}, isInTargetGroup.Results)
+	assert.Equal(t, 1, len(isInTargetGroup.SubExpressions))
+
+	isInPercentage := isInTargetGroup.SubExpressions[0].Steps[0]
+	assert.Equal(t, "isInPercentage", isInPercentage.Operator)
+	assert.Equal(t, []interface{}{0.5, "device2.seed2"}, isInPercentage.Operands)
+	assert.Equal(t, 0.18721251450181298, isInPercentage.Details["bucket"])
+
+	andSecond := trace.Steps[2]
+	assert.Equal(t, "and", andSecond.Operator)
+	assert.Equal(t, []interface{}{true}, andSecond.Operands)
+	assert.Equal(t, []interface{}{true}, andSecond.Results)
+}
+
+func TestExperimentsExtensionsWillReportTargetGroupEvaluationError(t *testing.T) {
+	parser := roxx.NewParser()
+	var errs []*roxx.EvaluationError
+	parser.SetEvaluationErrorHandler(func(err *roxx.EvaluationError) {
+		errs = append(errs, err)
+	})
+	targetGroupsRepository := repositories.NewTargetGroupRepository()
+	targetGroupsRepository.SetTargetGroups([]*model.TargetGroupModel{
+		model.NewTargetGroupModel("targetGroup1", `not("a")`),
+	})
+	experimentsExtensions := extensions.NewExperimentsExtensions(parser, targetGroupsRepository, nil, nil)
+	experimentsExtensions.Extend()
+
+	result := parser.EvaluateExpression(`isInTargetGroup("targetGroup1")`, nil)
+
+	assert.Equal(t, false, result.Value())
+	assert.Nil(t, result.Err())
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, `not("a")`, errs[0].Expression)
+	assert.Equal(t, "not", errs[0].Operator)
+}
+
+func TestExperimentsExtensionsStrictEvaluationWillFallBackToDefaultOnNestedError(t *testing.T) {
+	parser := roxx.NewParser()
+	var errs []*roxx.EvaluationError
+	parser.SetEvaluationErrorHandler(func(err *roxx.EvaluationError) {
+		errs = append(errs, err)
+	})
+	configurationRepository := repositories.NewConfigurationRepository()
+	flagRepository := repositories.NewFlagRepository()
+	flagSetter := entities.NewFlagSetter(flagRepository, parser, configurationRepository, nil)
+	extensions.NewExperimentsExtensions(parser, configurationRepository, flagRepository, configurationRepository).Extend()
+
+	inTargetGroup := entities.NewFlag(true)
+	flagRepository.AddFlag(inTargetGroup, "inTargetGroup")
+	dependent := entities.NewRoxString("default", []string{"default", "a", "b"})
+	flagRepository.AddFlag(dependent, "dependent")
+	configurationRepository.SetConfiguration([]*model.ExperimentModel{
+		model.NewExperimentModel("1", "inTargetGroup", `ifThen(isInTargetGroup("tg"), "true", "false")`, false, []string{"inTargetGroup"}, nil),
+		model.NewExperimentModel("2", "dependent", `ifThen(eq(flagValue("dependency"), "a"), "a", "b")`, false, []string{"dependent"}, nil),
+		model.NewExperimentModel("3", "dependency", `md5(not("a"))`, false, []string{"dependency"}, nil),
+	}, []*model.TargetGroupModel{
+		model.NewTargetGroupModel("tg", `not("a")`),
+	})
+	flagSetter.SetExperiments()
+
+	// the failing target group and flag dependency count as false
+	assert.False(t, inTargetGroup.IsEnabled(nil))
+	assert.Equal(t, "b", dependent.GetValue(nil))
+	assert.Equal(t, 2, len(errs))
+
+	parser.SetStrictEvaluation(true)
+	errs = nil
+
+	assert.True(t, inTargetGroup.IsEnabled(nil))
+	assert.Equal(t, "default", dependent.GetValue(nil))
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, `ifThen(isInTargetGroup("tg"), "true", "false")`, errs[0].Expression)
+	assert.Equal(t, "isInTargetGroup", errs[0].Operator)
+	var nested *roxx.EvaluationError
+	assert.True(t, errors.As(errs[0].Cause, &nested))
+	assert.Equal(t, `not("a")`, nested.Expression)
+	assert.Equal(t, "not", nested.Operator)
+	assert.Equal(t, "flagValue", errs[1].Operator)
+
+	result := parser.EvaluateExpression(`isInTargetGroup("tg")`, nil)
+	assert.Nil(t, result.Value())
+	assert.NotNil(t, result.Err())
+}
+
+func TestExperimentsExtensionsWillDetectTargetGroupCycle(t *testing.T) {
+	parser := roxx.NewParser()
+	var errs []*roxx.EvaluationError
+	parser.SetEvaluationErrorHandler(func(err *roxx.EvaluationError) {
+		errs = append(errs, err)
+	})
+	targetGroupsRepository := repositories.NewTargetGroupRepository()
+	targetGroupsRepository.SetTargetGroups([]*model.TargetGroupModel{
+		model.NewTargetGroupModel("targetGroup1", `isInTargetGroup("targetGroup2")`),
+		model.NewTargetGroupModel("targetGroup2", `or(false, isInTargetGroup("targetGroup1"))`),
+	})
+	experimentsExtensions := extensions.NewExperimentsExtensions(parser, targetGroupsRepository, nil, nil)
+	experimentsExtensions.Extend()
+
+	result := parser.EvaluateExpression(`not(isInTargetGroup("targetGroup1"))`, nil)
+
+	assert.Nil(t, result.Value())
+	assert.Equal(t, "isInTargetGroup", result.Err().Operator)
+	var limitError *roxx.EvaluationLimitError
+	assert.True(t, errors.As(result.Err(), &limitError))
+	assert.Equal(t, roxx.EvaluationLimitCycle, limitError.Limit)
+	assert.Equal(t, []string{"target group targetGroup1", "target group targetGroup2", "target group targetGroup1"}, limitError.Dependencies)
+	assert.Equal(t, 1, len(errs))
+}
+
+func TestExperimentsExtensionsWillDetectFlagDependencyCycle(t *testing.T) {
+	parser := roxx.NewParser()
+	targetGroupsRepository := repositories.NewTargetGroupRepository()
+	experimentRepository := repositories.NewExperimentRepository()
+	flagRepository := repositories.NewFlagRepository()
+	experimentsExtensions := extensions.NewExperimentsExtensions(parser, targetGroupsRepository, flagRepository, experimentRepository)
+	experimentsExtensions.Extend()
+
+	f := entities.NewFlag(false)
+	flagRepository.AddFlag(f, "f1")
+	exp1 := model.NewExperimentModel("id1", "name1", `ifThen(eq("true", flagValue("v1")), "true", "false")`, false, nil, nil)
+	f.(model.InternalVariant).SetForEvaluation(parser, exp1, nil)
+
+	v := entities.NewRoxString("blue", []string{"red", "green"})
+	flagRepository.AddFlag(v, "v1")
+	exp2 := model.NewExperimentModel("id2", "name2", `ifThen(eq("true", flagValue("f1")), "red", "green")`, false, nil, nil)
+	v.(model.InternalVariant).SetForEvaluation(parser, exp2, nil)
+
+	assert.Equal(t, "blue", v.GetValueAsString(nil))
+	assert.Equal(t, false, f.(model.Flag).IsEnabled(nil))
+
+	result := parser.EvaluateExpression(`flagValue("v1")`, nil)
+	var limitError *roxx.EvaluationLimitError
+	assert.True(t, errors.As(result.Err(), &limitError))
+	assert.Equal(t, []string{"flag v1", "flag f1", "flag v1"}, limitError.Dependencies)
+}
+
+func TestExperimentsExtensionsWillEvaluateWithOneConfigurationAcrossSwaps(t *testing.T) {
+	parser := roxx.NewParser()
+	configurationRepository := repositories.NewConfigurationRepository()
+	flagRepository := repositories.NewFlagRepository()
+	flagSetter := entities.NewFlagSetter(flagRepository, parser, configurationRepository, nil)
+	extensions.NewExperimentsExtensions(parser, configurationRepository, flagRepository, configurationRepository).Extend()
+
+	inTargetGroup := entities.NewFlag(false)
+	flagRepository.AddFlag(inTargetGroup, "inTargetGroup")
+	dependent := entities.NewFlag(false)
+	flagRepository.AddFlag(dependent, "dependent")
+	dependency := entities.NewRoxString("c", []string{"a", "b"})
+	flagRepository.AddFlag(dependency, "dependency")
+
+	// every flag is true as long as its condition and what it depends on come from the same configuration
+	configurations := []func(){
+		func() {
+			configurationRepository.SetConfiguration([]*model.ExperimentModel{
+				model.NewExperimentModel("1", "inTargetGroup", `isInTargetGroup("tg")`, false, []string{"inTargetGroup"}, nil),
+				model.NewExperimentModel("2", "dependent", `ifThen(eq(flagValue("dependency"), "a"), "true", "false")`, false, []string{"dependent"}, nil),
+				model.NewExperimentModel("3", "dependency", `"a"`, false, []string{"dependency"}, nil),
+			}, []*model.TargetGroupModel{
+				model.NewTargetGroupModel("tg", `true`),
+			})
+		},
+		func() {
+			configurationRepository.SetConfiguration([]*model.ExperimentModel{
+				model.NewExperimentModel("1", "inTargetGroup", `not(isInTargetGroup("tg"))`, false, []string{"inTargetGroup"}, nil),
+				model.NewExperimentModel("2", "dependent", `ifThen(eq(flagValue("dependency"), "b"), "true", "false")`, false, []string{"dependent"}, nil),
+				model.NewExperimentModel("3", "dependency", `"b"`, false, []string{"dependency"}, nil),
+			}, []*model.TargetGroupModel{
+				model.NewTargetGroupModel("tg", `false`),
+			})
+		},
+	}
+	configurations[0]()
+	flagSetter.SetExperiments()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 2000; i++ {
+			configurations[i%2]()
+			flagSetter.SetExperiments()
+		}
+		close(done)
+	}()
+
+	for evaluating := true; evaluating; {
+		select {
+		case <-done:
+			evaluating = false
+		default:
+		}
+		if !inTargetGroup.IsEnabled(nil) || !dependent.IsEnabled(nil) {
+			assert.Fail(t, "flag evaluated with conditions from different configurations")
+			break
+		}
+	}
+	wg.Wait()
+}
+
+func TestExperimentsExtensionsWillKeepConfigurationWhenSwappedDuringEvaluation(t *testing.T) {
+	parser := roxx.NewParser()
+	configurationRepository := repositories.NewConfigurationRepository()
+	flagRepository := repositories.NewFlagRepository()
+	flagSetter := entities.NewFlagSetter(flagRepository, parser, configurationRepository, nil)
+	extensions.NewExperimentsExtensions(parser, configurationRepository, flagRepository, configurationRepository).Extend()
+
+	inTargetGroup := entities.NewFlag(false)
+	flagRepository.AddFlag(inTargetGroup, "inTargetGroup")
+	dependent := entities.NewFlag(false)
+	flagRepository.AddFlag(dependent, "dependent")
+	dependency := entities.NewRoxString("c", []string{"a", "b"})
+	flagRepository.AddFlag(dependency, "dependency")
+
+	apply := func(value string, inTargetGroupCondition string) {
+		configurationRepository.SetConfiguration([]*model.ExperimentModel{
+			model.NewExperimentModel("1", "inTargetGroup", inTargetGroupCondition, false, []string{"inTargetGroup"}, nil),
+			model.NewExperimentModel("2", "dependent", `ifThen(and(swap(), eq(flagValue("dependency"), "`+value+`")), "true", "false")`, false, []string{"dependent"}, nil),
+			model.NewExperimentModel("3", "dependency", `"`+value+`"`, false, []string{"dependency"}, nil),
+		}, []*model.TargetGroupModel{
+			model.NewTargetGroupModel("tg", `eq("`+value+`", "a")`),
+		})
+		flagSetter.SetExperiments()
+	}
+	// swap applies the other configuration in the middle of the evaluation
+	swapped := false
+	parser.AddOperatorWithSignature("swap", roxx.NewOperatorSignature(roxx.OperandTypeBoolean), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		if swapped {
+			apply("a", `ifThen(and(swap(), isInTargetGroup("tg")), "true", "false")`)
+		} else {
+			apply("b", `ifThen(and(swap(), not(isInTargetGroup("tg"))), "true", "false")`)
+		}
+		swapped = !swapped
+		stack.Push(true)
+	})
+
+	apply("a", `ifThen(and(swap(), isInTargetGroup("tg")), "true", "false")`)
+
+	assert.True(t, inTargetGroup.IsEnabled(nil))
+	assert.True(t, inTargetGroup.IsEnabled(nil))
+	assert.True(t, dependent.IsEnabled(nil))
+	assert.True(t, dependent.IsEnabled(nil))
+}
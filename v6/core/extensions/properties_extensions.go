@@ -21,7 +21,7 @@ func NewPropertiesExtensions(parser roxx.Parser, propertiesRepository model.Cust
 }
 
 func (e *PropertiesExtensions) Extend() {
-	e.parser.AddOperator("property", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("property", roxx.NewOperatorSignature(roxx.OperandTypeAny, roxx.OperandTypeString), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
 		propName := stack.Pop().(string)
 		property := e.propertiesRepository.GetCustomProperty(propName)
 
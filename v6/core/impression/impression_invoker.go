@@ -14,6 +14,7 @@ type impressionInvoker struct {
 	deviceProperties         model.DeviceProperties
 	analytics                model.Analytics
 	isRoxy                   bool
+	logger                   logging.Logger
 
 	impressionHandlers []model.ImpressionHandler
 	handlersMutex      sync.RWMutex
@@ -25,15 +26,18 @@ type ImpressionsDeps struct {
 	DeviceProperties         model.DeviceProperties
 	Analytics                model.Analytics
 	IsRoxy                   bool
+	Logger                   logging.Logger
 }
 
 func NewImpressionInvoker(deps *ImpressionsDeps) model.ImpressionInvoker {
+	deps.Logger = logging.OrGlobal(deps.Logger)
 	return &impressionInvoker{
 		internalFlags:            deps.InternalFlags,
 		customPropertyRepository: deps.CustomPropertyRepository,
 		deviceProperties:         deps.DeviceProperties,
 		analytics:                deps.Analytics,
 		isRoxy:                   deps.IsRoxy,
+		logger:                   deps.Logger,
 	}
 }
 
@@ -67,7 +71,7 @@ func (ii *impressionInvoker) raiseImpressionEvent(args model.ImpressionArgs) {
 
 	defer func() {
 		if r := recover(); r != nil {
-			logging.GetLogger().Error("Failed to execute impression handler, panic", r)
+			ii.logger.Error("Failed to execute impression handler, panic", r)
 		}
 	}()
 	for _, handler := range handlers {
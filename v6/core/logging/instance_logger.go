@@ -0,0 +1,48 @@
+package logging
+
+import "sync/atomic"
+
+type loggerHolder struct {
+	logger Logger
+}
+
+// InstanceLogger forwards to the logger set on it and falls back to the global logger until one is set,
+// so that every SDK instance can log to its own destination
+type InstanceLogger struct {
+	logger atomic.Value
+}
+
+func NewInstanceLogger() *InstanceLogger {
+	return &InstanceLogger{}
+}
+
+func (l *InstanceLogger) SetLogger(logger Logger) {
+	l.logger.Store(loggerHolder{logger})
+}
+
+func (l *InstanceLogger) getLogger() Logger {
+	if holder, ok := l.logger.Load().(loggerHolder); ok && holder.logger != nil {
+		return holder.logger
+	}
+	return GetLogger()
+}
+
+func (l *InstanceLogger) Debug(message string, err interface{}) {
+	l.getLogger().Debug(message, err)
+}
+
+func (l *InstanceLogger) Warn(message string, err interface{}) {
+	l.getLogger().Warn(message, err)
+}
+
+func (l *InstanceLogger) Error(message string, err interface{}) {
+	l.getLogger().Error(message, err)
+}
+
+// OrGlobal returns logger, or a logger forwarding to the global logger when logger is nil
+func OrGlobal(logger Logger) Logger {
+	if logger == nil {
+		return NewInstanceLogger()
+	}
+	return logger
+}
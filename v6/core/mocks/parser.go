@@ -18,3 +18,36 @@ func (m *Parser) EvaluateExpression(expression string, context context.Context)
 func (m *Parser) AddOperator(name string, operation roxx.Operation) {
 	m.Called(name, operation)
 }
+
+func (m *Parser) AddOperatorWithSignature(name string, signature roxx.OperatorSignature, operation roxx.Operation) {
+	m.Called(name, signature, operation)
+}
+
+func (m *Parser) Operators() []string {
+	return roxx.NewParser().Operators()
+}
+
+func (m *Parser) OperatorSignature(name string) (roxx.OperatorSignature, bool) {
+	return roxx.NewParser().OperatorSignature(name)
+}
+
+func (m *Parser) CompileExpression(expression string) *roxx.CompiledExpression {
+	return roxx.NewParser().CompileExpression(expression)
+}
+
+func (m *Parser) EvaluateCompiledExpression(compiled *roxx.CompiledExpression, context context.Context) roxx.EvaluationResult {
+	return m.EvaluateExpression(compiled.Expression(), context)
+}
+
+func (m *Parser) ExplainExpression(expression string, context context.Context) (roxx.EvaluationResult, *roxx.ExpressionTrace) {
+	result := m.EvaluateExpression(expression, context)
+	return result, &roxx.ExpressionTrace{Expression: expression, Result: result.Value()}
+}
+
+func (m *Parser) SetEvaluationErrorHandler(handler roxx.EvaluationErrorHandler) {
+	m.Called(handler)
+}
+
+func (m *Parser) SetStrictEvaluation(strict bool) {
+	m.Called(strict)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/rollout/rox-go/v6/core/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,3 +20,11 @@ func (m *Request) SendPost(uri string, content interface{}) (response *model.Res
 	args := m.Called(uri, content)
 	return args.Get(0).(*model.Response), args.Error(1)
 }
+
+func (m *Request) SendGetContext(ctx context.Context, requestData model.RequestData) (response *model.Response, err error) {
+	return m.SendGet(requestData)
+}
+
+func (m *Request) SendPostContext(ctx context.Context, uri string, content interface{}) (response *model.Response, err error) {
+	return m.SendPost(uri, content)
+}
@@ -1,8 +1,10 @@
 package mocks
 
 import (
+	"net/http"
 	"time"
 
+	"github.com/rollout/rox-go/v6/core/logging"
 	"github.com/rollout/rox-go/v6/core/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -90,3 +92,81 @@ func (m *RoxOptions) AnalyticsQueueSize() int {
 	args := m.Called()
 	return args.Int(0)
 }
+
+func (m *RoxOptions) EmbeddedConfiguration() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *RoxOptions) ConfigurationCache() model.ConfigurationCache {
+	args := m.Called()
+	result := args.Get(0)
+	if result == nil {
+		return nil
+	}
+	return result.(model.ConfigurationCache)
+}
+
+func (m *RoxOptions) ConfigurationFilePath() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *RoxOptions) FetchRetryPolicy() *model.FetchRetryPolicy {
+	args := m.Called()
+	result := args.Get(0)
+	if result == nil {
+		return nil
+	}
+	return result.(*model.FetchRetryPolicy)
+}
+
+func (m *RoxOptions) HTTPClient() *http.Client {
+	args := m.Called()
+	result := args.Get(0)
+	if result == nil {
+		return nil
+	}
+	return result.(*http.Client)
+}
+
+func (m *RoxOptions) RequestInterceptors() []model.RequestInterceptor {
+	args := m.Called()
+	result := args.Get(0)
+	if result == nil {
+		return nil
+	}
+	return result.([]model.RequestInterceptor)
+}
+
+func (m *RoxOptions) ResponseInterceptors() []model.ResponseInterceptor {
+	args := m.Called()
+	result := args.Get(0)
+	if result == nil {
+		return nil
+	}
+	return result.([]model.ResponseInterceptor)
+}
+
+func (m *RoxOptions) Logger() logging.Logger {
+	args := m.Called()
+	result := args.Get(0)
+	if result == nil {
+		return nil
+	}
+	return result.(logging.Logger)
+}
+
+func (m *RoxOptions) EvaluationErrorHandler() model.EvaluationErrorHandler {
+	args := m.Called()
+	result := args.Get(0)
+	if result == nil {
+		return nil
+	}
+	return result.(model.EvaluationErrorHandler)
+}
+
+func (m *RoxOptions) IsStrictEvaluationEnabled() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
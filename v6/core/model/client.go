@@ -2,9 +2,11 @@ package model
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/logging"
 )
 
 type BUID interface {
@@ -49,6 +51,36 @@ type RoxOptions interface {
 	DynamicPropertyRuleHandler() DynamicPropertyRuleHandler
 	NetworkConfigurationsOptions() NetworkConfigurationsOptions
 	IsSignatureVerificationDisabled() bool
+	EmbeddedConfiguration() string
+	ConfigurationCache() ConfigurationCache
+	ConfigurationFilePath() string
+	FetchRetryPolicy() *FetchRetryPolicy
+	HTTPClient() *http.Client
+	RequestInterceptors() []RequestInterceptor
+	ResponseInterceptors() []ResponseInterceptor
+	Logger() logging.Logger
+	EvaluationErrorHandler() EvaluationErrorHandler
+	IsStrictEvaluationEnabled() bool
+}
+
+// FetchRetryPolicy controls how failed configuration fetches are retried
+type FetchRetryPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter is the fraction (0-1) of each delay that is randomly taken off, nil keeps the default and 0 retries after the exact delays
+	Jitter *float64
+}
+
+// DefaultFetchRetryPolicy is the policy used when the options do not have one
+func DefaultFetchRetryPolicy() *FetchRetryPolicy {
+	jitter := 0.5
+	return &FetchRetryPolicy{
+		InitialDelay: 2 * time.Second,
+		MaxDelay:     5 * time.Minute,
+		Multiplier:   2,
+		Jitter:       &jitter,
+	}
 }
 
 type SdkSettings interface {
@@ -73,3 +105,7 @@ type DynamicPropertyRuleHandlerArgs struct {
 	PropName string
 	Context  context.Context
 }
+
+// EvaluationErrorHandler is called when a flag condition, or an expression it evaluates such as a target group, fails.
+// err is a *roxx.EvaluationError, the flag then falls back to its default value
+type EvaluationErrorHandler = func(err error)
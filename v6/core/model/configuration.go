@@ -21,8 +21,15 @@ const (
 	FetcherStatusAppliedFromLocalStorage
 	FetcherStatusAppliedFromNetwork
 	FetcherStatusErrorFetchedFailed
+	FetcherStatusAppliedFromFile
 )
 
+// ConfigurationCache persists the last verified configuration so it can be applied on the next Setup
+type ConfigurationCache interface {
+	Load() (configuration string, err error)
+	Save(configuration string) error
+}
+
 type ConfigurationFetchedHandler = func(args *ConfigurationFetchedArgs)
 
 type ConfigurationFetchedArgs struct {
@@ -30,6 +37,24 @@ type ConfigurationFetchedArgs struct {
 	CreationDate  time.Time
 	HasChanges    bool
 	ErrorDetails  FetcherError
+	// Changes lists the flags and target groups that differ from the previously applied configuration
+	Changes ConfigurationChanges
+}
+
+// ConfigurationChanges is the difference between two applied configurations, all the lists are sorted
+type ConfigurationChanges struct {
+	// AddedFlags are flags that now have an experiment and had none before
+	AddedFlags []string
+	// RemovedFlags are flags that had an experiment and have none now
+	RemovedFlags []string
+	// ChangedFlags are flags whose experiment was replaced or modified
+	ChangedFlags []string
+	// ChangedTargetGroups are target groups that were added, removed or modified
+	ChangedTargetGroups []string
+}
+
+func (c ConfigurationChanges) IsEmpty() bool {
+	return len(c.AddedFlags) == 0 && len(c.RemovedFlags) == 0 && len(c.ChangedFlags) == 0 && len(c.ChangedTargetGroups) == 0
 }
 
 func NewConfigurationFetchedArgs(fetcherStatus FetcherStatus, creationDate time.Time, hasChanges bool) ConfigurationFetchedArgs {
@@ -52,8 +52,29 @@ type InternalVariant interface {
 	SetName(name string)
 	SetContext(globalContext context.Context)
 	SetForEvaluation(parser roxx.Parser, experiment *ExperimentModel, impressionInvoker ImpressionInvoker)
+	// SetForConfiguration is like SetForEvaluation with the experiment of the variant in configuration,
+	// the flags and target groups its condition depends on are then evaluated with the same configuration
+	SetForConfiguration(parser roxx.Parser, configuration ConfigurationSnapshot, impressionInvoker ImpressionInvoker)
+	// EvaluateAsString returns the same value as GetValueAsString without sending an impression
+	EvaluateAsString(context context.Context) string
+	// Explain evaluates the variant like GetValueAsString, without sending an impression, and describes how the value was computed
+	Explain(context context.Context) *FlagExplanation
 }
 
+// FlagExplanation describes how the value of a flag was computed
+type FlagExplanation struct {
+	FlagName   string      `json:"flagName"`
+	Experiment *Experiment `json:"experiment,omitempty"`
+	Condition  string      `json:"condition,omitempty"`
+	Value      string      `json:"value"`
+	IsDefault  bool        `json:"isDefault"`
+	// Trace is nil when the flag has no condition to evaluate
+	Trace *roxx.ExpressionTrace `json:"trace,omitempty"`
+}
+
+// FlagChangedHandler is called with the previous and the new value of a watched flag
+type FlagChangedHandler = func(oldValue, newValue string)
+
 type InternalRoxString interface {
 	InternalGetValue(ctx context.Context) (returnValue string, isDefault bool)
 }
@@ -1,26 +1,44 @@
 package model
 
-import "net/url"
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
 
 type Request interface {
 	SendGet(requestData RequestData) (response *Response, err error)
 	SendPost(uri string, content interface{}) (response *Response, err error)
+	SendGetContext(ctx context.Context, requestData RequestData) (response *Response, err error)
+	SendPostContext(ctx context.Context, uri string, content interface{}) (response *Response, err error)
 }
 
+// RequestInterceptor is called before every request the SDK sends, returning an error aborts the request
+type RequestInterceptor = func(request *http.Request) error
+
+// ResponseInterceptor is called after every request the SDK sends, it must not consume the response body
+type ResponseInterceptor = func(request *http.Request, response *http.Response, err error)
+
 type RequestData struct {
 	URL         string
 	QueryParams map[string]string
+	Headers     map[string]string
 }
 
 type Response struct {
 	StatusCode int
 	Content    []byte
+	Header     http.Header
 }
 
 func (r Response) IsSuccessStatusCode() bool {
 	return 200 <= r.StatusCode && r.StatusCode < 300
 }
 
+func (r Response) IsNotModified() bool {
+	return r.StatusCode == http.StatusNotModified
+}
+
 func (requestData RequestData) URLWithQuery() (*url.URL, error) {
 	uri, err := url.Parse(requestData.URL)
 	if err != nil {
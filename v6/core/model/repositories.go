@@ -1,6 +1,9 @@
 package model
 
-import "github.com/rollout/rox-go/v6/core/properties"
+import (
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/properties"
+)
 
 type CustomPropertyAddedHandler = func(property *properties.CustomProperty)
 
@@ -32,3 +35,34 @@ type TargetGroupRepository interface {
 	SetTargetGroups(targetGroups []*TargetGroupModel)
 	GetTargetGroup(id string) *TargetGroupModel
 }
+
+// ConfigurationRepository holds the experiments and the target groups of the applied configuration as one snapshot
+type ConfigurationRepository interface {
+	ExperimentRepository
+	TargetGroupRepository
+	SetConfiguration(experiments []*ExperimentModel, targetGroups []*TargetGroupModel)
+	Snapshot() ConfigurationSnapshot
+}
+
+// ConfigurationSnapshot is an applied configuration, it is never modified
+type ConfigurationSnapshot interface {
+	GetExperimentByFlag(flagName string) *ExperimentModel
+	GetAllExperiments() []*ExperimentModel
+	GetTargetGroup(id string) *TargetGroupModel
+}
+
+// configurationContextKey is the context key the configuration of the running evaluation is found with,
+// its type keeps the configuration out of the user's keys
+type configurationContextKey struct{}
+
+// ContextWithConfiguration returns ctx carrying configuration, the target groups and flags a flag condition depends on
+// are then taken from it rather than from the configuration applied meanwhile
+func ContextWithConfiguration(ctx context.Context, configuration ConfigurationSnapshot) context.Context {
+	return context.NewValueContext(ctx, configurationContextKey{}, configuration)
+}
+
+// ConfigurationFromContext returns the configuration ctx carries, if any
+func ConfigurationFromContext(ctx context.Context) ConfigurationSnapshot {
+	configuration, _ := context.Value(ctx, configurationContextKey{}).(ConfigurationSnapshot)
+	return configuration
+}
@@ -0,0 +1,25 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type emptySnapshot struct{}
+
+func (emptySnapshot) GetExperimentByFlag(flagName string) *model.ExperimentModel { return nil }
+func (emptySnapshot) GetAllExperiments() []*model.ExperimentModel                { return nil }
+func (emptySnapshot) GetTargetGroup(id string) *model.TargetGroupModel           { return nil }
+
+func TestContextWithConfigurationWillHideConfigurationFromUserKeys(t *testing.T) {
+	userContext := context.NewContext(map[string]interface{}{"rox.configuration": "user"})
+	ctx := model.ContextWithConfiguration(userContext, emptySnapshot{})
+
+	assert.Equal(t, emptySnapshot{}, model.ConfigurationFromContext(ctx))
+	assert.Equal(t, "user", ctx.Get("rox.configuration"))
+	assert.Nil(t, model.ConfigurationFromContext(userContext))
+	assert.Nil(t, model.ConfigurationFromContext(nil))
+}
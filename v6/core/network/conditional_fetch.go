@@ -0,0 +1,83 @@
+package network
+
+import (
+	"crypto/md5"
+	"sync"
+
+	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/rollout/rox-go/v6/core/model"
+)
+
+// conditionalFetch remembers the last fetched configuration so that unchanged
+// configurations are neither downloaded nor parsed again
+type conditionalFetch struct {
+	etag         string
+	lastModified string
+	contentHash  [md5.Size]byte
+	lastResult   *configuration.FetchResult
+	mutex        sync.Mutex
+}
+
+func (c *conditionalFetch) withValidators(requestData model.RequestData) model.RequestData {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.lastResult == nil || (c.etag == "" && c.lastModified == "") {
+		return requestData
+	}
+
+	headers := make(map[string]string, len(requestData.Headers)+2)
+	for k, v := range requestData.Headers {
+		headers[k] = v
+	}
+	if c.etag != "" {
+		headers["If-None-Match"] = c.etag
+	}
+	if c.lastModified != "" {
+		headers["If-Modified-Since"] = c.lastModified
+	}
+	requestData.Headers = headers
+	return requestData
+}
+
+func (c *conditionalFetch) notModified(source configuration.Source) *configuration.FetchResult {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.lastResult == nil {
+		return nil
+	}
+	return &configuration.FetchResult{ParsedData: c.lastResult.ParsedData, Source: source, NotModified: true}
+}
+
+func (c *conditionalFetch) fetchResult(response *model.Response, source configuration.Source) *configuration.FetchResult {
+	hash := md5.Sum(response.Content)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.lastResult != nil && hash == c.contentHash {
+		c.storeValidators(response)
+		return &configuration.FetchResult{ParsedData: c.lastResult.ParsedData, Source: source, NotModified: true}
+	}
+
+	result := configuration.NewFetchResult(string(response.Content), source)
+	if result == nil {
+		return nil
+	}
+
+	c.contentHash = hash
+	c.lastResult = result
+	c.storeValidators(response)
+	return result
+}
+
+// storeValidators keeps the previous validators a response does not repeat
+func (c *conditionalFetch) storeValidators(response *model.Response) {
+	if etag := response.Header.Get("ETag"); etag != "" {
+		c.etag = etag
+	}
+	if lastModified := response.Header.Get("Last-Modified"); lastModified != "" {
+		c.lastModified = lastModified
+	}
+}
@@ -1,14 +1,17 @@
 package network
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/rollout/rox-go/v6/core/logging"
 	"github.com/rollout/rox-go/v6/core/model"
 )
 
 type ConfigurationFetcher interface {
 	Fetch() *configuration.FetchResult
+	FetchContext(ctx context.Context) *configuration.FetchResult
 }
 
 type configurationFetcher struct {
@@ -16,18 +19,24 @@ type configurationFetcher struct {
 	requestConfigurationBuilder RequestConfigurationBuilder
 	request                     model.Request
 	fetcherLogger               configurationFetcherLogger
+	cdnFetch                    conditionalFetch
+	apiFetch                    conditionalFetch
 }
 
-func NewConfigurationFetcher(environment model.Environment, requestConfigurationBuilder RequestConfigurationBuilder, request model.Request, fetchedInvoker *configuration.FetchedInvoker) ConfigurationFetcher {
+func NewConfigurationFetcher(environment model.Environment, requestConfigurationBuilder RequestConfigurationBuilder, request model.Request, fetchedInvoker *configuration.FetchedInvoker, logger logging.Logger) ConfigurationFetcher {
 	return &configurationFetcher{
 		environment:                 environment,
 		requestConfigurationBuilder: requestConfigurationBuilder,
 		request:                     request,
-		fetcherLogger:               configurationFetcherLogger{fetchedInvoker},
+		fetcherLogger:               newConfigurationFetcherLogger(fetchedInvoker, logger),
 	}
 }
 
 func (f *configurationFetcher) Fetch() *configuration.FetchResult {
+	return f.FetchContext(context.Background())
+}
+
+func (f *configurationFetcher) FetchContext(ctx context.Context) *configuration.FetchResult {
 	shouldRetry := false
 	source := configuration.SourceCDN
 
@@ -42,18 +51,24 @@ func (f *configurationFetcher) Fetch() *configuration.FetchResult {
 	isSelfManaged := f.environment.IsSelfManaged()
 
 	if !isSelfManaged {
-		fetchResult, err = f.fetchFromCDN()
+		fetchResult, err = f.fetchFromCDN(ctx)
 		if err != nil {
 			f.fetcherLogger.WriteFetchExceptionToLogAndInvokeFetchHandler(source, err)
 			return nil
 		}
 
-		if fetchResult.IsSuccessStatusCode() {
-			configurationFetchResult := configuration.NewFetchResult(string(fetchResult.Content), source)
+		var configurationFetchResult *configuration.FetchResult
+		if fetchResult.IsNotModified() {
+			// an unchanged CDN miss still has to fall back to the API
+			configurationFetchResult = f.cdnFetch.notModified(source)
+		} else if fetchResult.IsSuccessStatusCode() {
+			configurationFetchResult = f.cdnFetch.fetchResult(fetchResult, source)
 			if configurationFetchResult == nil {
 				return nil
 			}
+		}
 
+		if configurationFetchResult != nil {
 			if configurationFetchResult.ParsedData.Result == 404 {
 				shouldRetry = true
 			} else {
@@ -67,14 +82,14 @@ func (f *configurationFetcher) Fetch() *configuration.FetchResult {
 			f.fetcherLogger.WriteFetchErrorToLog(source, fetchResult, configuration.SourceAPI)
 		}
 		source = configuration.SourceAPI
-		fetchResult, err = f.fetchFromAPI()
+		fetchResult, err = f.fetchFromAPI(ctx)
 		if err != nil {
 			f.fetcherLogger.WriteFetchExceptionToLogAndInvokeFetchHandler(source, err)
 			return nil
 		}
 
 		if fetchResult.IsSuccessStatusCode() {
-			return configuration.NewFetchResult(string(fetchResult.Content), source)
+			return f.apiFetch.fetchResult(fetchResult, source)
 		}
 	}
 
@@ -82,11 +97,11 @@ func (f *configurationFetcher) Fetch() *configuration.FetchResult {
 	return nil
 }
 
-func (f *configurationFetcher) fetchFromCDN() (response *model.Response, err error) {
-	return f.request.SendGet(f.requestConfigurationBuilder.BuildForCDN())
+func (f *configurationFetcher) fetchFromCDN(ctx context.Context) (response *model.Response, err error) {
+	return f.request.SendGetContext(ctx, f.cdnFetch.withValidators(f.requestConfigurationBuilder.BuildForCDN()))
 }
 
-func (f *configurationFetcher) fetchFromAPI() (response *model.Response, err error) {
+func (f *configurationFetcher) fetchFromAPI(ctx context.Context) (response *model.Response, err error) {
 	requestData := f.requestConfigurationBuilder.BuildForAPI()
-	return f.request.SendPost(requestData.URL, requestData.QueryParams)
+	return f.request.SendPostContext(ctx, requestData.URL, requestData.QueryParams)
 }
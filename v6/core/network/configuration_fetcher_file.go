@@ -0,0 +1,97 @@
+package network
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/rollout/rox-go/v6/core/logging"
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/utils"
+)
+
+const DefaultFilePollInterval = 5 * time.Second
+
+// ConfigurationWatcher is implemented by fetchers that can detect configuration changes on their own
+type ConfigurationWatcher interface {
+	Watch(onChange func(), quit <-chan struct{})
+}
+
+type configurationFetcherFile struct {
+	path          string
+	pollInterval  time.Duration
+	fetcherLogger configurationFetcherLogger
+	fileFetch     conditionalFetch
+
+	lastModTime time.Time
+	lastSize    int64
+	mutex       sync.Mutex
+}
+
+func NewConfigurationFetcherFile(path string, pollInterval time.Duration, fetchedInvoker *configuration.FetchedInvoker, logger logging.Logger) ConfigurationFetcher {
+	return &configurationFetcherFile{
+		path:          path,
+		pollInterval:  pollInterval,
+		fetcherLogger: newConfigurationFetcherLogger(fetchedInvoker, logger),
+	}
+}
+
+func (f *configurationFetcherFile) Fetch() *configuration.FetchResult {
+	return f.FetchContext(context.Background())
+}
+
+func (f *configurationFetcherFile) FetchContext(ctx context.Context) *configuration.FetchResult {
+	source := configuration.SourceFile
+
+	if err := ctx.Err(); err != nil {
+		f.fetcherLogger.WriteFetchExceptionToLogAndInvokeFetchHandler(source, err)
+		return nil
+	}
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		f.fetcherLogger.WriteFetchExceptionToLogAndInvokeFetchHandler(source, err)
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		f.fetcherLogger.WriteFetchExceptionToLogAndInvokeFetchHandler(source, err)
+		return nil
+	}
+
+	f.mutex.Lock()
+	f.lastModTime = info.ModTime()
+	f.lastSize = info.Size()
+	f.mutex.Unlock()
+
+	result := f.fileFetch.fetchResult(&model.Response{Content: data}, source)
+	if result == nil {
+		// the last configuration stays applied until the file is fixed
+		f.fetcherLogger.WriteCorruptedConfigurationToLogAndInvokeFetchHandler(source)
+	}
+	return result
+}
+
+func (f *configurationFetcherFile) Watch(onChange func(), quit <-chan struct{}) {
+	utils.RunPeriodicTask(func() {
+		if f.hasChanged() {
+			onChange()
+		}
+	}, f.pollInterval, quit)
+}
+
+func (f *configurationFetcherFile) hasChanged() bool {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		// keep serving the last configuration while the file is missing (e.g. during a ConfigMap update)
+		return false
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return !info.ModTime().Equal(f.lastModTime) || info.Size() != f.lastSize
+}
@@ -0,0 +1,95 @@
+package network_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigurationFetcherFileWillReturnFileData(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "rox-file")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "configuration.json")
+	ioutil.WriteFile(path, []byte("{\"data\": \"harti\"}"), 0600)
+
+	confFetchInvoker := configuration.NewFetchedInvoker()
+	numberOfTimesCalled := 0
+	confFetchInvoker.RegisterFetchedHandler(func(e *model.ConfigurationFetchedArgs) {
+		numberOfTimesCalled++
+	})
+
+	confFetcher := network.NewConfigurationFetcherFile(path, time.Second, confFetchInvoker, nil)
+	result := confFetcher.Fetch()
+
+	assert.Equal(t, "harti", result.ParsedData.Data)
+	assert.Equal(t, configuration.SourceFile, result.Source)
+	assert.Equal(t, 0, numberOfTimesCalled)
+}
+
+func TestConfigurationFetcherFileWillReturnNullWhenFileIsMissing(t *testing.T) {
+	confFetchInvoker := configuration.NewFetchedInvoker()
+	numberOfTimesCalled := 0
+	confFetchInvoker.RegisterFetchedHandler(func(e *model.ConfigurationFetchedArgs) {
+		numberOfTimesCalled++
+	})
+
+	confFetcher := network.NewConfigurationFetcherFile("/nonexistent/configuration.json", time.Second, confFetchInvoker, nil)
+	result := confFetcher.Fetch()
+
+	assert.Nil(t, result)
+	assert.Equal(t, 1, numberOfTimesCalled)
+}
+
+func TestConfigurationFetcherFileWillNotifyOnChange(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "rox-file")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "configuration.json")
+	ioutil.WriteFile(path, []byte("{\"data\": \"harti\"}"), 0600)
+
+	confFetcher := network.NewConfigurationFetcherFile(path, 10*time.Millisecond, configuration.NewFetchedInvoker(), nil)
+	confFetcher.Fetch()
+
+	changed := make(chan struct{}, 1)
+	quit := make(chan struct{})
+	defer close(quit)
+	confFetcher.(network.ConfigurationWatcher).Watch(func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}, quit)
+
+	ioutil.WriteFile(path, []byte("{\"data\": \"harti-changed\"}"), 0600)
+
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		assert.Fail(t, "watcher was not notified about the file change")
+	}
+}
+
+func TestConfigurationFetcherFileWillReportCorruptedJSON(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "rox-file")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "configuration.json")
+	ioutil.WriteFile(path, []byte("{\"data\": "), 0600)
+
+	confFetchInvoker := configuration.NewFetchedInvoker()
+	var errors []model.FetcherError
+	confFetchInvoker.RegisterFetchedHandler(func(e *model.ConfigurationFetchedArgs) {
+		errors = append(errors, e.ErrorDetails)
+	})
+
+	confFetcher := network.NewConfigurationFetcherFile(path, time.Second, confFetchInvoker, nil)
+	result := confFetcher.Fetch()
+
+	assert.Nil(t, result)
+	assert.Equal(t, []model.FetcherError{model.FetcherErrorCorruptedJSON}, errors)
+}
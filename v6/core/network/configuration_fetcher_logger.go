@@ -10,19 +10,29 @@ import (
 
 type configurationFetcherLogger struct {
 	fetchedInvoker *configuration.FetchedInvoker
+	logger         logging.Logger
+}
+
+func newConfigurationFetcherLogger(fetchedInvoker *configuration.FetchedInvoker, logger logging.Logger) configurationFetcherLogger {
+	return configurationFetcherLogger{fetchedInvoker: fetchedInvoker, logger: logging.OrGlobal(logger)}
 }
 
 func (fl *configurationFetcherLogger) WriteFetchErrorToLogAndInvokeFetchHandler(source configuration.Source, response *model.Response) {
-	logging.GetLogger().Debug(fmt.Sprintf("Failed to fetch from %s. http error code: %d\n", source, response.StatusCode), nil)
+	fl.logger.Debug(fmt.Sprintf("Failed to fetch from %s. http error code: %d\n", source, response.StatusCode), nil)
 	fl.fetchedInvoker.InvokeError(model.FetcherErrorNetwork)
 }
 
 func (fl *configurationFetcherLogger) WriteFetchErrorToLog(source configuration.Source, response *model.Response, nextSource configuration.Source) {
 	retryMsg := fmt.Sprintf("Trying from %s. ", nextSource)
-	logging.GetLogger().Debug(fmt.Sprintf("Failed to fetch from %s. %shttp error code: %d\n", source, retryMsg, response.StatusCode), nil)
+	fl.logger.Debug(fmt.Sprintf("Failed to fetch from %s. %shttp error code: %d\n", source, retryMsg, response.StatusCode), nil)
+}
+
+func (fl *configurationFetcherLogger) WriteCorruptedConfigurationToLogAndInvokeFetchHandler(source configuration.Source) {
+	fl.logger.Error(fmt.Sprintf("Failed to parse configuration. Source: %s\n", source), nil)
+	fl.fetchedInvoker.InvokeError(model.FetcherErrorCorruptedJSON)
 }
 
 func (fl *configurationFetcherLogger) WriteFetchExceptionToLogAndInvokeFetchHandler(source configuration.Source, ex interface{}) {
-	logging.GetLogger().Error(fmt.Sprintf("Failed to fetch configuration. Source: %s. Ex: %s\n", source, ex), nil)
+	fl.logger.Error(fmt.Sprintf("Failed to fetch configuration. Source: %s. Ex: %s\n", source, ex), nil)
 	fl.fetchedInvoker.InvokeError(model.FetcherErrorNetwork)
 }
@@ -1,7 +1,10 @@
 package network
 
 import (
+	"context"
+
 	"github.com/rollout/rox-go/v6/core/configuration"
+	"github.com/rollout/rox-go/v6/core/logging"
 	"github.com/rollout/rox-go/v6/core/model"
 )
 
@@ -9,17 +12,22 @@ type configurationFetcherRoxy struct {
 	requestConfigurationBuilder RequestConfigurationBuilder
 	request                     model.Request
 	fetcherLogger               configurationFetcherLogger
+	roxyFetch                   conditionalFetch
 }
 
-func NewConfigurationFetcherRoxy(requestConfigurationBuilder RequestConfigurationBuilder, request model.Request, fetchedInvoker *configuration.FetchedInvoker) ConfigurationFetcher {
+func NewConfigurationFetcherRoxy(requestConfigurationBuilder RequestConfigurationBuilder, request model.Request, fetchedInvoker *configuration.FetchedInvoker, logger logging.Logger) ConfigurationFetcher {
 	return &configurationFetcherRoxy{
 		requestConfigurationBuilder: requestConfigurationBuilder,
 		request:                     request,
-		fetcherLogger:               configurationFetcherLogger{fetchedInvoker},
+		fetcherLogger:               newConfigurationFetcherLogger(fetchedInvoker, logger),
 	}
 }
 
 func (f *configurationFetcherRoxy) Fetch() *configuration.FetchResult {
+	return f.FetchContext(context.Background())
+}
+
+func (f *configurationFetcherRoxy) FetchContext(ctx context.Context) *configuration.FetchResult {
 	source := configuration.SourceRoxy
 
 	defer func() {
@@ -28,20 +36,26 @@ func (f *configurationFetcherRoxy) Fetch() *configuration.FetchResult {
 		}
 	}()
 
-	fetchResult, err := f.fetchFromRoxy()
+	fetchResult, err := f.fetchFromRoxy(ctx)
 	if err != nil {
 		f.fetcherLogger.WriteFetchExceptionToLogAndInvokeFetchHandler(source, err)
 		return nil
 	}
 
+	if fetchResult.IsNotModified() {
+		if configurationFetchResult := f.roxyFetch.notModified(source); configurationFetchResult != nil {
+			return configurationFetchResult
+		}
+	}
+
 	if fetchResult.IsSuccessStatusCode() {
-		return configuration.NewFetchResult(string(fetchResult.Content), source)
+		return f.roxyFetch.fetchResult(fetchResult, source)
 	}
 
 	f.fetcherLogger.WriteFetchErrorToLogAndInvokeFetchHandler(source, fetchResult)
 	return nil
 }
 
-func (f *configurationFetcherRoxy) fetchFromRoxy() (response *model.Response, err error) {
-	return f.request.SendGet(f.requestConfigurationBuilder.BuildForRoxy())
+func (f *configurationFetcherRoxy) fetchFromRoxy(ctx context.Context) (response *model.Response, err error) {
+	return f.request.SendGetContext(ctx, f.roxyFetch.withValidators(f.requestConfigurationBuilder.BuildForRoxy()))
 }
@@ -27,7 +27,7 @@ func TestConfigurationFetcherRoxyWillReturnCDNDataWhenSuccessful(t *testing.T) {
 	requestBuilder := &mocks.RequestConfigurationBuilder{}
 	requestBuilder.On("BuildForRoxy").Return(requestData)
 
-	confFetcher := network.NewConfigurationFetcherRoxy(requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcherRoxy(requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Equal(t, "harti", result.ParsedData.Data)
@@ -49,7 +49,7 @@ func TestConfigurationFetcherRoxyWillReturnNullWhenRoxyFailsWithException(t *tes
 	requestBuilder := &mocks.RequestConfigurationBuilder{}
 	requestBuilder.On("BuildForRoxy").Return(requestData)
 
-	confFetcher := network.NewConfigurationFetcherRoxy(requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcherRoxy(requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Nil(t, result)
@@ -71,7 +71,7 @@ func TestConfigurationFetcherRoxyWillReturnNullWhenRoxyFailsWithHttpStatus(t *te
 	requestBuilder := &mocks.RequestConfigurationBuilder{}
 	requestBuilder.On("BuildForRoxy").Return(requestData)
 
-	confFetcher := network.NewConfigurationFetcherRoxy(requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcherRoxy(requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Nil(t, result)
@@ -30,7 +30,7 @@ func TestConfigurationFetcherWillReturnCDNDataWhenSuccessful(t *testing.T) {
 	requestBuilder := &mocks.RequestConfigurationBuilder{}
 	requestBuilder.On("BuildForCDN").Return(requestData)
 
-	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Equal(t, "harti", result.ParsedData.Data)
@@ -57,7 +57,7 @@ func TestConfigurationFetcherWillReturnNullWhenCDNFailsWithException(t *testing.
 	requestBuilder.On("BuildForCDN").Return(requestDataCDN)
 	requestBuilder.On("BuildForAPI").Return(requestDataAPI)
 
-	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Nil(t, result)
@@ -83,7 +83,7 @@ func TestConfigurationFetcherWillReturnNullWhenCDNFails404APIWithException(t *te
 	requestBuilder.On("BuildForCDN").Return(requestDataCDN)
 	requestBuilder.On("BuildForAPI").Return(requestDataAPI)
 
-	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Nil(t, result)
@@ -110,7 +110,7 @@ func TestConfigurationFetcherWillReturnAPIDataWhenCDNFails404APIOK(t *testing.T)
 	requestBuilder.On("BuildForCDN").Return(requestDataCDN)
 	requestBuilder.On("BuildForAPI").Return(requestDataAPI)
 
-	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Equal(t, "harto", result.ParsedData.Data)
@@ -138,7 +138,7 @@ func TestConfigurationFetcherWillReturnNullDataWhenBothNotFound(t *testing.T) {
 	requestBuilder.On("BuildForCDN").Return(requestDataCDN)
 	requestBuilder.On("BuildForAPI").Return(requestDataAPI)
 
-	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Nil(t, result)
@@ -164,9 +164,108 @@ func TestConfigurationFetcherWillReturnAPIDataWhenSelfManaged(t *testing.T) {
 	requestBuilder := &mocks.RequestConfigurationBuilder{}
 	requestBuilder.On("BuildForAPI").Return(requestDataAPI)
 
-	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker)
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker, nil)
 	result := confFetcher.Fetch()
 
 	assert.Nil(t, result)
 	assert.Equal(t, 1, numberOfTimesCalled)
 }
+
+func TestConfigurationFetcherWillSendValidatorsAndHandleNotModified(t *testing.T) {
+	confFetchInvoker := configuration.NewFetchedInvoker()
+	numberOfTimesCalled := 0
+	confFetchInvoker.RegisterFetchedHandler(func(e *model.ConfigurationFetchedArgs) {
+		numberOfTimesCalled++
+	})
+
+	requestData := model.RequestData{URL: "harta.com"}
+	conditionalRequestData := model.RequestData{URL: "harta.com", Headers: map[string]string{"If-None-Match": `"v1"`}}
+	request := &mocks.Request{}
+	response := &model.Response{StatusCode: http.StatusOK, Content: []byte("{\"data\": \"harti\"}"), Header: http.Header{"Etag": []string{`"v1"`}}}
+	notModifiedResponse := &model.Response{StatusCode: http.StatusNotModified}
+	request.On("SendGet", requestData).Return(response, nil)
+	request.On("SendGet", conditionalRequestData).Return(notModifiedResponse, nil)
+
+	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
+	requestBuilder := &mocks.RequestConfigurationBuilder{}
+	requestBuilder.On("BuildForCDN").Return(requestData)
+
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, confFetchInvoker, nil)
+	first := confFetcher.Fetch()
+	second := confFetcher.Fetch()
+
+	assert.False(t, first.NotModified)
+	assert.True(t, second.NotModified)
+	assert.Equal(t, "harti", second.ParsedData.Data)
+	assert.Equal(t, 0, numberOfTimesCalled)
+	request.AssertCalled(t, "SendGet", conditionalRequestData)
+}
+
+func TestConfigurationFetcherWillMarkUnchangedContentAsNotModified(t *testing.T) {
+	requestData := model.RequestData{URL: "harta.com"}
+	request := &mocks.Request{}
+	response := &model.Response{StatusCode: http.StatusOK, Content: []byte("{\"data\": \"harti\"}")}
+	request.On("SendGet", requestData).Return(response, nil)
+
+	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
+	requestBuilder := &mocks.RequestConfigurationBuilder{}
+	requestBuilder.On("BuildForCDN").Return(requestData)
+
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, configuration.NewFetchedInvoker(), nil)
+	first := confFetcher.Fetch()
+	second := confFetcher.Fetch()
+
+	assert.False(t, first.NotModified)
+	assert.True(t, second.NotModified)
+	assert.Equal(t, first.ParsedData, second.ParsedData)
+}
+
+func TestConfigurationFetcherWillFallBackToAPIWhenCDNMissIsNotModified(t *testing.T) {
+	requestDataCDN := model.RequestData{URL: "harta1.com"}
+	conditionalRequestDataCDN := model.RequestData{URL: "harta1.com", Headers: map[string]string{"If-None-Match": `"v1"`}}
+	requestDataAPI := model.RequestData{URL: "harta2.com"}
+	request := &mocks.Request{}
+	responseCDN := &model.Response{StatusCode: http.StatusOK, Content: []byte("{\"result\": 404}"), Header: http.Header{"Etag": []string{`"v1"`}}}
+	notModifiedResponseCDN := &model.Response{StatusCode: http.StatusNotModified}
+	response := &model.Response{StatusCode: http.StatusOK, Content: []byte("{\"data\": \"harto\"}")}
+	request.On("SendGet", requestDataCDN).Return(responseCDN, nil)
+	request.On("SendGet", conditionalRequestDataCDN).Return(notModifiedResponseCDN, nil)
+	request.On("SendPost", requestDataAPI.URL, requestDataAPI.QueryParams).Return(response, nil)
+
+	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
+	requestBuilder := &mocks.RequestConfigurationBuilder{}
+	requestBuilder.On("BuildForCDN").Return(requestDataCDN)
+	requestBuilder.On("BuildForAPI").Return(requestDataAPI)
+
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, configuration.NewFetchedInvoker(), nil)
+	first := confFetcher.Fetch()
+	second := confFetcher.Fetch()
+
+	assert.Equal(t, "harto", first.ParsedData.Data)
+	assert.Equal(t, "harto", second.ParsedData.Data)
+	assert.Equal(t, configuration.SourceAPI, second.Source)
+	request.AssertCalled(t, "SendGet", conditionalRequestDataCDN)
+	request.AssertNumberOfCalls(t, "SendPost", 2)
+}
+
+func TestConfigurationFetcherWillKeepValidatorsMissingFromResponse(t *testing.T) {
+	requestData := model.RequestData{URL: "harta.com"}
+	conditionalRequestData := model.RequestData{URL: "harta.com", Headers: map[string]string{"If-None-Match": `"v1"`}}
+	request := &mocks.Request{}
+	response := &model.Response{StatusCode: http.StatusOK, Content: []byte("{\"data\": \"harti\"}"), Header: http.Header{"Etag": []string{`"v1"`}}}
+	changedResponse := &model.Response{StatusCode: http.StatusOK, Content: []byte("{\"data\": \"harto\"}")}
+	request.On("SendGet", requestData).Return(response, nil)
+	request.On("SendGet", conditionalRequestData).Return(changedResponse, nil)
+
+	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
+	requestBuilder := &mocks.RequestConfigurationBuilder{}
+	requestBuilder.On("BuildForCDN").Return(requestData)
+
+	confFetcher := network.NewConfigurationFetcher(environment, requestBuilder, request, configuration.NewFetchedInvoker(), nil)
+	confFetcher.Fetch()
+	confFetcher.Fetch()
+	confFetcher.Fetch()
+
+	request.AssertNumberOfCalls(t, "SendGet", 3)
+	assert.Equal(t, conditionalRequestData, request.Calls[2].Arguments.Get(0))
+}
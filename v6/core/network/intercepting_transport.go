@@ -0,0 +1,57 @@
+package network
+
+import (
+	"net/http"
+
+	"github.com/rollout/rox-go/v6/core/model"
+)
+
+type interceptingTransport struct {
+	transport            http.RoundTripper
+	requestInterceptors  []model.RequestInterceptor
+	responseInterceptors []model.ResponseInterceptor
+}
+
+// NewInterceptingClient returns a copy of client whose requests and responses pass through the given interceptors
+func NewInterceptingClient(client *http.Client, requestInterceptors []model.RequestInterceptor, responseInterceptors []model.ResponseInterceptor) *http.Client {
+	if len(requestInterceptors) == 0 && len(responseInterceptors) == 0 {
+		return client
+	}
+
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	interceptingClient := *client
+	interceptingClient.Transport = &interceptingTransport{
+		transport:            transport,
+		requestInterceptors:  requestInterceptors,
+		responseInterceptors: responseInterceptors,
+	}
+	return &interceptingClient
+}
+
+func (t *interceptingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the caller's request
+	request = request.Clone(request.Context())
+	for _, interceptor := range t.requestInterceptors {
+		if err := interceptor(request); err != nil {
+			if request.Body != nil {
+				request.Body.Close()
+			}
+			t.interceptResponse(request, nil, err)
+			return nil, err
+		}
+	}
+
+	response, err := t.transport.RoundTrip(request)
+	t.interceptResponse(request, response, err)
+	return response, err
+}
+
+func (t *interceptingTransport) interceptResponse(request *http.Request, response *http.Response, err error) {
+	for _, interceptor := range t.responseInterceptors {
+		interceptor(request, response, err)
+	}
+}
@@ -0,0 +1,63 @@
+package network_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptingClientWillMutateRequestsAndInspectResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	statusCode := 0
+	client := network.NewInterceptingClient(http.DefaultClient,
+		[]model.RequestInterceptor{func(request *http.Request) error {
+			request.Header.Set("Authorization", "Bearer harti")
+			return nil
+		}},
+		[]model.ResponseInterceptor{func(request *http.Request, response *http.Response, err error) {
+			statusCode = response.StatusCode
+		}})
+
+	response, err := network.NewRequest(client).SendGet(model.RequestData{URL: server.URL})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer harti", string(response.Content))
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Nil(t, http.DefaultClient.Transport)
+}
+
+func TestInterceptingClientWillAbortRequestWhenInterceptorFails(t *testing.T) {
+	numberOfRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numberOfRequests++
+	}))
+	defer server.Close()
+
+	var interceptedErr error
+	client := network.NewInterceptingClient(http.DefaultClient,
+		[]model.RequestInterceptor{func(request *http.Request) error {
+			return errors.New("no token")
+		}},
+		[]model.ResponseInterceptor{func(request *http.Request, response *http.Response, err error) {
+			interceptedErr = err
+		}})
+
+	_, err := network.NewRequest(client).SendGet(model.RequestData{URL: server.URL})
+
+	assert.NotNil(t, err)
+	assert.EqualError(t, interceptedErr, "no token")
+	assert.Equal(t, 0, numberOfRequests)
+}
+
+func TestInterceptingClientWillReturnSameClientWithoutInterceptors(t *testing.T) {
+	assert.Same(t, http.DefaultClient, network.NewInterceptingClient(http.DefaultClient, nil, nil))
+}
@@ -3,6 +3,7 @@ package network
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -20,15 +21,22 @@ func NewRequest(httpClient *http.Client) model.Request {
 }
 
 func (r *request) SendGet(requestData model.RequestData) (*model.Response, error) {
+	return r.SendGetContext(context.Background(), requestData)
+}
+
+func (r *request) SendGetContext(ctx context.Context, requestData model.RequestData) (*model.Response, error) {
 	uri, err := requestData.URLWithQuery()
 	if err != nil {
 		return nil, err
 	}
-	request, err := http.NewRequest("GET", uri.String(), nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", uri.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 	request.Header.Add("Accept-Encoding", "gzip")
+	for k, v := range requestData.Headers {
+		request.Header.Set(k, v)
+	}
 
 	resp, err := r.httpClient.Do(request)
 	if err != nil {
@@ -39,13 +47,17 @@ func (r *request) SendGet(requestData model.RequestData) (*model.Response, error
 }
 
 func (r *request) SendPost(uri string, content interface{}) (*model.Response, error) {
+	return r.SendPostContext(context.Background(), uri, content)
+}
+
+func (r *request) SendPostContext(ctx context.Context, uri string, content interface{}) (*model.Response, error) {
 	data, err := json.Marshal(content)
 	if err != nil {
 		return nil, err
 	}
 	buf := bytes.NewBuffer(data)
 
-	request, err := http.NewRequest("POST", uri, buf)
+	request, err := http.NewRequestWithContext(ctx, "POST", uri, buf)
 	if err != nil {
 		return nil, err
 	}
@@ -77,5 +89,5 @@ func (r *request) readBody(resp *http.Response) (*model.Response, error) {
 	}
 
 	respContent, err := ioutil.ReadAll(reader)
-	return &model.Response{resp.StatusCode, respContent}, err
+	return &model.Response{StatusCode: resp.StatusCode, Content: respContent, Header: resp.Header}, err
 }
@@ -45,8 +45,8 @@ func (b *requestConfigurationBuilder) GetPath() string {
 
 func (b *requestConfigurationBuilder) BuildForCDN() model.RequestData {
 	return model.RequestData{
-		fmt.Sprintf("%s/%s", b.environment.EnvironmentCDNPath(), b.GetPath()),
-		map[string]string{consts.PropertyTypeDistinctID.Name: b.deviceProperties.DistinctID()},
+		URL:         fmt.Sprintf("%s/%s", b.environment.EnvironmentCDNPath(), b.GetPath()),
+		QueryParams: map[string]string{consts.PropertyTypeDistinctID.Name: b.deviceProperties.DistinctID()},
 	}
 }
 
@@ -72,5 +72,5 @@ func (b *requestConfigurationBuilder) buildRequestWithFullParams(uri string) mod
 	queryParams[consts.PropertyTypeCacheMissRelativeURL.Name] = b.GetPath()
 	queryParams[consts.PropertyTypeDevModeSecret.Name] = b.sdkSettings.DevModeSecret()
 
-	return model.RequestData{uri, queryParams}
+	return model.RequestData{URL: uri, QueryParams: queryParams}
 }
@@ -40,9 +40,10 @@ type StateSender struct {
 	stateDebouncer           utils.Debouncer
 	environment              model.Environment
 	useNewPlatformFormat     bool
+	logger                   logging.Logger
 }
 
-func NewStateSender(r model.Request, deviceProperties model.DeviceProperties, flagRepository model.FlagRepository, customPropertyRepository model.CustomPropertyRepository, environment model.Environment, useNewPlatformFormat bool) *StateSender {
+func NewStateSender(r model.Request, deviceProperties model.DeviceProperties, flagRepository model.FlagRepository, customPropertyRepository model.CustomPropertyRepository, environment model.Environment, useNewPlatformFormat bool, logger logging.Logger) *StateSender {
 	stateSender := &StateSender{
 		customPropertyRepository: customPropertyRepository,
 		deviceProperties:         deviceProperties,
@@ -50,6 +51,7 @@ func NewStateSender(r model.Request, deviceProperties model.DeviceProperties, fl
 		request:                  r,
 		environment:              environment,
 		useNewPlatformFormat:     useNewPlatformFormat,
+		logger:                   logging.OrGlobal(logger),
 	}
 	stateSender.stateDebouncer = *utils.NewDebouncer(3000, func() {
 		stateSender.Send()
@@ -269,11 +271,11 @@ func (s *StateSender) Send() {
 
 func (s *StateSender) logSendStateErrorRetry(source configuration.Source, response *model.Response, nextSource configuration.Source) {
 	retryMsg := fmt.Sprintf("Trying from %s. ", nextSource)
-	logging.GetLogger().Debug(fmt.Sprintf("Failed to send state to %s. %shttp error code: %d\n", source, retryMsg, response.StatusCode), nil)
+	s.logger.Debug(fmt.Sprintf("Failed to send state to %s. %shttp error code: %d\n", source, retryMsg, response.StatusCode), nil)
 }
 
 func (s *StateSender) logSendStateError(source configuration.Source, err error) {
-	logging.GetLogger().Debug(fmt.Sprintf("Failed to send state. Source: %s", err), nil)
+	s.logger.Debug(fmt.Sprintf("Failed to send state. Source: %s", err), nil)
 }
 
 type jsonFlag struct {
@@ -43,7 +43,7 @@ func TestWillSerializeFlags(t *testing.T) {
 	cpRepo := repositories.NewCustomPropertyRepository()
 	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
 
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 
 	serializedFlags, featureFlags := stateSender.serializeFeatureFlags()
 	var flags []map[string]interface{}
@@ -87,7 +87,7 @@ func TestWillSerializeFlagsNewPlatformFormat(t *testing.T) {
 	cpRepo := repositories.NewCustomPropertyRepository()
 	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
 
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, true)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, true, nil)
 
 	serializedFlags, featureFlags := stateSender.serializeFeatureFlags()
 	var flags []map[string]interface{}
@@ -169,7 +169,7 @@ func TestWillSerializeProps(t *testing.T) {
 	cpRepo.On("RegisterPropertyAddedHandler", mock.Anything).Return()
 	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
 
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 
 	var props []map[string]interface{}
 	serializedCustomProperties, customProperties := stateSender.serializeCustomProperties()
@@ -204,7 +204,7 @@ func TestWillSerializePropsInNewPlatformFormat(t *testing.T) {
 	cpRepo.On("RegisterPropertyAddedHandler", mock.Anything).Return()
 	environment := client.NewSaasEnvironment(consts.ROLLOUT_API)
 
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, true)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, true, nil)
 
 	var props []map[string]interface{}
 	serializedCustomProperties, customProperties := stateSender.serializeCustomProperties()
@@ -260,7 +260,7 @@ func TestWillCallOnlyCDNStateMD5ChangesForFlag(t *testing.T) {
 	})
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag1")
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "C1C65A5AC8A732EAB7FCD81017BF5A87"), requestData.URL)
@@ -286,7 +286,7 @@ func TestWillCallOnlyCDNStateMD5ChangesForCustomProperty(t *testing.T) {
 	})
 
 	cpRepo.AddCustomProperty(properties.NewStringProperty("cp1", "true"))
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "02338C470874941BEB8335F76A0F0FBB"), requestData.URL)
@@ -313,7 +313,7 @@ func TestWillCallOnlyCDNStateMD5FlagOrderNotImportant(t *testing.T) {
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag1")
 	flagRepo.AddFlag(entities.NewFlag(false), "flag2")
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "F367809AB0CCA5A05EA9DFB3C4E9E15C"), requestData.URL)
@@ -322,7 +322,7 @@ func TestWillCallOnlyCDNStateMD5FlagOrderNotImportant(t *testing.T) {
 	flagRepo2 := repositories.NewFlagRepository()
 	flagRepo2.AddFlag(entities.NewFlag(false), "flag2")
 	flagRepo2.AddFlag(entities.NewFlag(false), "flag1")
-	stateSender = NewStateSender(request, dp, flagRepo2, cpRepo, environment, false)
+	stateSender = NewStateSender(request, dp, flagRepo2, cpRepo, environment, false, nil)
 	stateSender.Send()
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "F367809AB0CCA5A05EA9DFB3C4E9E15C"), requestData.URL)
 }
@@ -343,7 +343,7 @@ func TestWillCallOnlyCDNStateMD5CustomPropertyOrderNotImportant(t *testing.T) {
 
 	cpRepo.AddCustomProperty(properties.NewStringProperty("cp1", "1111"))
 	cpRepo.AddCustomProperty(properties.NewStringProperty("cp2", "2222"))
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "8BB417F48703DDBD07EC0C2F2160B4B2"), requestData.URL)
@@ -375,7 +375,7 @@ func TestWillReturnNullWhenCDNFailsWithException(t *testing.T) {
 	})
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag")
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "00C4910E8BA69D08C65D05849C9E6DFB"), reqCDNData.URL)
@@ -403,7 +403,7 @@ func TestWillReturnNullWhenCDNSucceedWithEmptyResponse(t *testing.T) {
 	})
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag")
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "00C4910E8BA69D08C65D05849C9E6DFB"), reqCDNData.URL)
@@ -431,7 +431,7 @@ func TestWillReturnNullWhenCDNSucceedWithNotJsonResponse(t *testing.T) {
 	})
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag")
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "00C4910E8BA69D08C65D05849C9E6DFB"), reqCDNData.URL)
@@ -460,7 +460,7 @@ func TestWillReturnNullWhenCDNFails404APIWithException(t *testing.T) {
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag")
 	cpRepo.AddCustomProperty(properties.NewStringProperty("id", "1111"))
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "996ABD4ED5D9D4DF02E56C39ED1F701C"), reqCDNData.URL)
@@ -489,7 +489,7 @@ func TestWillReturnAPIDataWhenCDNSucceedWithResult404APIOK(t *testing.T) {
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag")
 	cpRepo.AddCustomProperty(properties.NewStringProperty("id", "1111"))
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", consts.EnvironmentStateCDNPath(consts.ROLLOUT_API), appKey, "996ABD4ED5D9D4DF02E56C39ED1F701C"), reqCDNData.URL)
@@ -518,7 +518,7 @@ func TestWillReturnAPIDataWhenWhenSelfManaged(t *testing.T) {
 
 	flagRepo.AddFlag(entities.NewFlag(false), "flag")
 	cpRepo.AddCustomProperty(properties.NewStringProperty("id", "1111"))
-	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false)
+	stateSender := NewStateSender(request, dp, flagRepo, cpRepo, environment, false, nil)
 	stateSender.Send()
 
 	assert.Equal(t, fmt.Sprintf("%s/%s/%s", "http://harta2.com/device/update_state_store", appKey, "996ABD4ED5D9D4DF02E56C39ED1F701C"), reqAPIData)
@@ -3,6 +3,7 @@ package notifications
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -23,19 +24,23 @@ type Event struct {
 type EventHandler = func(event Event)
 
 type NotificationListener struct {
-	listenURL string
-	appKey    string
+	listenURL  string
+	appKey     string
+	httpClient *http.Client
+	logger     logging.Logger
 
 	handlers      map[string][]EventHandler
 	handlersMutex sync.RWMutex
 	stop          chan struct{}
 }
 
-func NewNotificationListener(listenURL, appKey string) *NotificationListener {
+func NewNotificationListener(listenURL, appKey string, httpClient *http.Client, logger logging.Logger) *NotificationListener {
 	return &NotificationListener{
-		listenURL: listenURL,
-		appKey:    appKey,
-		handlers:  make(map[string][]EventHandler),
+		listenURL:  listenURL,
+		appKey:     appKey,
+		httpClient: httpClient,
+		logger:     logging.OrGlobal(logger),
+		handlers:   make(map[string][]EventHandler),
 	}
 }
 
@@ -58,10 +63,16 @@ func (nl *NotificationListener) On(eventName string, handler EventHandler) {
 func (nl *NotificationListener) run(sseURL string) {
 
 	sseClient := sse.NewClientWithoutRetry(sseURL)
+	if nl.httpClient != nil {
+		// the event stream is long-lived, so it must not be cut by the client's request timeout
+		connection := *nl.httpClient
+		connection.Timeout = 0
+		sseClient.Connection = &connection
+	}
 	events := make(chan *sse.Event)
 	sseCloser, err := sseClient.SubscribeChan("", events)
 	if err != nil {
-		logging.GetLogger().Warn("Can't subscribe to SSE events", err)
+		nl.logger.Warn("Can't subscribe to SSE events", err)
 		time.Sleep(connectionRetryInterval)
 
 		select {
@@ -81,7 +92,7 @@ func (nl *NotificationListener) readEvents(events <-chan *sse.Event, sseCloser i
 		case <-nl.stop:
 			err := sseCloser.Close()
 			if err != nil {
-				logging.GetLogger().Warn("Can't close SSE closer", err)
+				nl.logger.Warn("Can't close SSE closer", err)
 			}
 			return
 		case event, ok := <-events:
@@ -109,7 +120,7 @@ func (nl *NotificationListener) invokeHandlers(rawEvent *sse.Event) {
 func (nl *NotificationListener) invokeHandler(handler EventHandler, event Event) {
 	defer func() {
 		if r := recover(); r != nil {
-			logging.GetLogger().Error(fmt.Sprintf("SSE handler panics: %s", r), nil)
+			nl.logger.Error(fmt.Sprintf("SSE handler panics: %s", r), nil)
 		}
 	}()
 
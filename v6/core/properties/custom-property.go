@@ -1,6 +1,7 @@
 package properties
 
 import (
+	"net"
 	"time"
 
 	"github.com/rollout/rox-go/v6/core/context"
@@ -13,6 +14,8 @@ type CustomFloatPropertyGenerator = func(context context.Context) float64
 type CustomTimePropertyGenerator = func(context context.Context) time.Time
 type CustomBooleanPropertyGenerator = func(context context.Context) bool
 type CustomSemverPropertyGenerator = func(context context.Context) string
+type CustomStringListPropertyGenerator = func(context context.Context) []string
+type CustomIPPropertyGenerator = func(context context.Context) net.IP
 
 type CustomProperty struct {
 	Name  string
@@ -56,6 +59,18 @@ func NewSemverProperty(name string, value string) *CustomProperty {
 	})
 }
 
+func NewStringListProperty(name string, value []string) *CustomProperty {
+	return NewComputedStringListProperty(name, func(context context.Context) []string {
+		return value
+	})
+}
+
+func NewIPProperty(name string, value net.IP) *CustomProperty {
+	return NewComputedIPProperty(name, func(context context.Context) net.IP {
+		return value
+	})
+}
+
 func NewComputedStringProperty(name string, value CustomStringPropertyGenerator) *CustomProperty {
 	return &CustomProperty{
 		Name: name,
@@ -115,3 +130,36 @@ func NewComputedSemverProperty(name string, value CustomSemverPropertyGenerator)
 		},
 	}
 }
+
+func NewComputedStringListProperty(name string, value CustomStringListPropertyGenerator) *CustomProperty {
+	return &CustomProperty{
+		Name: name,
+		Type: CustomPropertyTypeStringList,
+		Value: func(context context.Context) interface{} {
+			list := value(context)
+			if list == nil {
+				return nil
+			}
+
+			items := make([]interface{}, len(list))
+			for i, item := range list {
+				items[i] = item
+			}
+			return items
+		},
+	}
+}
+
+func NewComputedIPProperty(name string, value CustomIPPropertyGenerator) *CustomProperty {
+	return &CustomProperty{
+		Name: name,
+		Type: CustomPropertyTypeIP,
+		Value: func(context context.Context) interface{} {
+			ip := value(context)
+			if ip == nil {
+				return nil
+			}
+			return ip.String()
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package properties_test
 
 import (
+	"net"
 	"testing"
 
 	"github.com/rollout/rox-go/v6/core/context"
@@ -38,6 +39,18 @@ func TestCustomPropertyWillCreatePropertyWithConstValue(t *testing.T) {
 	assert.Equal(t, "prop1", propSemver.Name)
 	assert.Equal(t, properties.CustomPropertyTypeSemver, propSemver.Type)
 	assert.Equal(t, "1.2.3", propSemver.Value(nil))
+
+	propStringList := properties.NewStringListProperty("prop1", []string{"a", "b"})
+
+	assert.Equal(t, "prop1", propStringList.Name)
+	assert.Equal(t, properties.CustomPropertyTypeStringList, propStringList.Type)
+	assert.Equal(t, []interface{}{"a", "b"}, propStringList.Value(nil))
+
+	propIP := properties.NewIPProperty("prop1", net.ParseIP("10.1.2.3"))
+
+	assert.Equal(t, "prop1", propIP.Name)
+	assert.Equal(t, properties.CustomPropertyTypeIP, propIP.Type)
+	assert.Equal(t, "10.1.2.3", propIP.Value(nil))
 }
 
 func TestCustomPropertyWillCreatePropertyWithFuncValue(t *testing.T) {
@@ -80,6 +93,34 @@ func TestCustomPropertyWillCreatePropertyWithFuncValue(t *testing.T) {
 	assert.Equal(t, "prop1", propSemver.Name)
 	assert.Equal(t, properties.CustomPropertyTypeSemver, propSemver.Type)
 	assert.Equal(t, "1.2.3", propSemver.Value(nil))
+
+	propStringList := properties.NewComputedStringListProperty("prop1", func(context context.Context) []string {
+		return []string{"a", "b"}
+	})
+
+	assert.Equal(t, "prop1", propStringList.Name)
+	assert.Equal(t, properties.CustomPropertyTypeStringList, propStringList.Type)
+	assert.Equal(t, []interface{}{"a", "b"}, propStringList.Value(nil))
+
+	propNilStringList := properties.NewComputedStringListProperty("prop1", func(context context.Context) []string {
+		return nil
+	})
+
+	assert.Nil(t, propNilStringList.Value(nil))
+
+	propIP := properties.NewComputedIPProperty("prop1", func(context context.Context) net.IP {
+		return net.ParseIP("2001:db8::1")
+	})
+
+	assert.Equal(t, "prop1", propIP.Name)
+	assert.Equal(t, properties.CustomPropertyTypeIP, propIP.Type)
+	assert.Equal(t, "2001:db8::1", propIP.Value(nil))
+
+	propNilIP := properties.NewComputedIPProperty("prop1", func(context context.Context) net.IP {
+		return nil
+	})
+
+	assert.Nil(t, propNilIP.Value(nil))
 }
 
 func TestCustomPropertyWillPassContext(t *testing.T) {
@@ -12,4 +12,8 @@ var (
 	CustomPropertyTypeFloat  = &CustomPropertyType{"double", "Number"}
 	CustomPropertyTypeSemver = &CustomPropertyType{"semver", "Semver"}
 	CustomPropertyTypeTime   = &CustomPropertyType{"time", "DateTime"}
+	// CustomPropertyTypeStringList values are pushed to roxx as []interface{} holding strings
+	CustomPropertyTypeStringList = &CustomPropertyType{"stringlist", "StringList"}
+	// CustomPropertyTypeIP values are pushed to roxx as the string form of the address
+	CustomPropertyTypeIP = &CustomPropertyType{"ip", "IP"}
 )
@@ -18,14 +18,16 @@ type errorReporter struct {
 	request          model.Request
 	deviceProperties model.DeviceProperties
 	buid             model.BUID
+	logger           logging.Logger
 }
 
-func NewErrorReporter(environment model.Environment, request model.Request, deviceProperties model.DeviceProperties, buid model.BUID) model.ErrorReporter {
+func NewErrorReporter(environment model.Environment, request model.Request, deviceProperties model.DeviceProperties, buid model.BUID, logger logging.Logger) model.ErrorReporter {
 	return &errorReporter{
 		environment:      environment,
 		request:          request,
 		deviceProperties: deviceProperties,
 		buid:             buid,
+		logger:           logging.OrGlobal(logger),
 	}
 }
 
@@ -34,7 +36,7 @@ func (er *errorReporter) Report(message string, err error) {
 		return
 	}
 
-	logging.GetLogger().Error(fmt.Sprintf("Error report: %s", message), err)
+	er.logger.Error(fmt.Sprintf("Error report: %s", message), err)
 
 	stackTrace := er.getStackTrace()
 
@@ -43,19 +45,19 @@ func (er *errorReporter) Report(message string, err error) {
 }
 
 func (er *errorReporter) sendError(payload interface{}) {
-	logging.GetLogger().Debug("Sending bugsnag error report...", nil)
+	er.logger.Debug("Sending bugsnag error report...", nil)
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				logging.GetLogger().Error("Failed to send bugsnag error", r)
+				er.logger.Error("Failed to send bugsnag error", r)
 			}
 		}()
 
 		_, err := er.request.SendPost(BugsnagNotifyUrl, payload)
 		if err != nil {
-			logging.GetLogger().Error("Failed to send bugsnag error", err)
+			er.logger.Error("Failed to send bugsnag error", err)
 		} else {
-			logging.GetLogger().Debug("Bugsnag error report was sent", nil)
+			er.logger.Debug("Bugsnag error report was sent", nil)
 		}
 	}()
 }
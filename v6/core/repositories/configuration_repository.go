@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/rollout/rox-go/v6/core/model"
+)
+
+// configurationSnapshot is never modified once stored, readers load it once and get a consistent view
+type configurationSnapshot struct {
+	experiments       []*model.ExperimentModel
+	experimentsByFlag map[string]*model.ExperimentModel
+	targetGroups      []*model.TargetGroupModel
+	targetGroupsByID  map[string]*model.TargetGroupModel
+}
+
+type configurationRepository struct {
+	snapshot   atomic.Value
+	writeMutex sync.Mutex
+}
+
+func NewConfigurationRepository() model.ConfigurationRepository {
+	r := &configurationRepository{}
+	r.snapshot.Store(newConfigurationSnapshot(nil, nil))
+	return r
+}
+
+func newConfigurationSnapshot(experiments []*model.ExperimentModel, targetGroups []*model.TargetGroupModel) *configurationSnapshot {
+	snapshot := &configurationSnapshot{
+		experiments:       experiments,
+		experimentsByFlag: make(map[string]*model.ExperimentModel),
+		targetGroups:      targetGroups,
+		targetGroupsByID:  make(map[string]*model.TargetGroupModel),
+	}
+	for _, e := range experiments {
+		for _, f := range e.Flags {
+			if _, ok := snapshot.experimentsByFlag[f]; !ok {
+				snapshot.experimentsByFlag[f] = e
+			}
+		}
+	}
+	for _, g := range targetGroups {
+		if _, ok := snapshot.targetGroupsByID[g.ID]; !ok {
+			snapshot.targetGroupsByID[g.ID] = g
+		}
+	}
+	return snapshot
+}
+
+func (r *configurationRepository) load() *configurationSnapshot {
+	return r.snapshot.Load().(*configurationSnapshot)
+}
+
+func (r *configurationRepository) SetConfiguration(experiments []*model.ExperimentModel, targetGroups []*model.TargetGroupModel) {
+	r.writeMutex.Lock()
+	r.snapshot.Store(newConfigurationSnapshot(experiments, targetGroups))
+	r.writeMutex.Unlock()
+}
+
+func (r *configurationRepository) SetExperiments(experiments []*model.ExperimentModel) {
+	r.writeMutex.Lock()
+	r.snapshot.Store(newConfigurationSnapshot(experiments, r.load().targetGroups))
+	r.writeMutex.Unlock()
+}
+
+func (r *configurationRepository) SetTargetGroups(targetGroups []*model.TargetGroupModel) {
+	r.writeMutex.Lock()
+	r.snapshot.Store(newConfigurationSnapshot(r.load().experiments, targetGroups))
+	r.writeMutex.Unlock()
+}
+
+func (r *configurationRepository) Snapshot() model.ConfigurationSnapshot {
+	return r.load()
+}
+
+func (r *configurationRepository) GetExperimentByFlag(flagName string) *model.ExperimentModel {
+	return r.load().GetExperimentByFlag(flagName)
+}
+
+func (r *configurationRepository) GetAllExperiments() []*model.ExperimentModel {
+	return r.load().GetAllExperiments()
+}
+
+func (r *configurationRepository) GetTargetGroup(id string) *model.TargetGroupModel {
+	return r.load().GetTargetGroup(id)
+}
+
+func (s *configurationSnapshot) GetExperimentByFlag(flagName string) *model.ExperimentModel {
+	return s.experimentsByFlag[flagName]
+}
+
+func (s *configurationSnapshot) GetAllExperiments() []*model.ExperimentModel {
+	return s.experiments
+}
+
+func (s *configurationSnapshot) GetTargetGroup(id string) *model.TargetGroupModel {
+	return s.targetGroupsByID[id]
+}
@@ -0,0 +1,59 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/model"
+	"github.com/rollout/rox-go/v6/core/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigurationRepositoryWillReplaceExperimentsAndTargetGroupsTogether(t *testing.T) {
+	repo := repositories.NewConfigurationRepository()
+	repo.SetConfiguration(
+		[]*model.ExperimentModel{model.NewExperimentModel("1", "1", "1", false, []string{"a"}, nil)},
+		[]*model.TargetGroupModel{model.NewTargetGroupModel("1", "x")})
+
+	repo.SetConfiguration(
+		[]*model.ExperimentModel{model.NewExperimentModel("2", "2", "2", false, []string{"b"}, nil)},
+		[]*model.TargetGroupModel{model.NewTargetGroupModel("2", "y")})
+
+	assert.Nil(t, repo.GetExperimentByFlag("a"))
+	assert.Equal(t, "2", repo.GetExperimentByFlag("b").ID)
+	assert.Nil(t, repo.GetTargetGroup("1"))
+	assert.Equal(t, "y", repo.GetTargetGroup("2").Condition)
+	assert.Equal(t, 1, len(repo.GetAllExperiments()))
+}
+
+func TestConfigurationRepositoryWillKeepTargetGroupsWhenSettingExperiments(t *testing.T) {
+	repo := repositories.NewConfigurationRepository()
+	repo.SetTargetGroups([]*model.TargetGroupModel{model.NewTargetGroupModel("1", "x")})
+	repo.SetExperiments([]*model.ExperimentModel{model.NewExperimentModel("1", "1", "1", false, []string{"a"}, nil)})
+
+	assert.Equal(t, "x", repo.GetTargetGroup("1").Condition)
+	assert.Equal(t, "1", repo.GetExperimentByFlag("a").ID)
+}
+
+func TestConfigurationRepositoryWillReturnFirstExperimentOfFlag(t *testing.T) {
+	repo := repositories.NewConfigurationRepository()
+	repo.SetExperiments([]*model.ExperimentModel{
+		model.NewExperimentModel("1", "1", "1", false, []string{"a"}, nil),
+		model.NewExperimentModel("2", "2", "2", false, []string{"a"}, nil),
+	})
+
+	assert.Equal(t, "1", repo.GetExperimentByFlag("a").ID)
+}
+
+func TestConfigurationRepositorySnapshotWillNotSeeLaterConfigurations(t *testing.T) {
+	repo := repositories.NewConfigurationRepository()
+	repo.SetConfiguration(
+		[]*model.ExperimentModel{model.NewExperimentModel("1", "1", "1", false, []string{"a"}, nil)},
+		[]*model.TargetGroupModel{model.NewTargetGroupModel("1", "x")})
+	snapshot := repo.Snapshot()
+
+	repo.SetConfiguration(nil, nil)
+
+	assert.Equal(t, "1", snapshot.GetExperimentByFlag("a").ID)
+	assert.Equal(t, "x", snapshot.GetTargetGroup("1").Condition)
+	assert.Nil(t, repo.GetTargetGroup("1"))
+}
@@ -14,11 +14,19 @@ type customPropertyRepository struct {
 
 	propertyAddedHandlers []model.CustomPropertyAddedHandler
 	handlersMutex         sync.RWMutex
+	logger                logging.Logger
 }
 
 func NewCustomPropertyRepository() model.CustomPropertyRepository {
+	return NewCustomPropertyRepositoryWithLogger(nil)
+}
+
+// NewCustomPropertyRepositoryWithLogger is like NewCustomPropertyRepository, a panic in the handler told about added
+// custom properties is recovered and logged to logger
+func NewCustomPropertyRepositoryWithLogger(logger logging.Logger) model.CustomPropertyRepository {
 	return &customPropertyRepository{
 		customProperties: make(map[string]*properties.CustomProperty),
+		logger:           logging.OrGlobal(logger),
 	}
 }
 
@@ -81,8 +89,8 @@ func (r *customPropertyRepository) raisePropertyAddedEvent(property *properties.
 	r.handlersMutex.RUnlock()
 
 	defer func() {
-		if r := recover(); r != nil {
-			logging.GetLogger().Error("Failed to execute custom property handler, panic", r)
+		if err := recover(); err != nil {
+			r.logger.Error("Failed to execute custom property handler, panic", err)
 		}
 	}()
 
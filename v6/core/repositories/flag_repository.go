@@ -13,11 +13,18 @@ type flagRepository struct {
 
 	flagAddedHandlers []model.FlagAddedHandler
 	handlersMutex     sync.RWMutex
+	logger            logging.Logger
 }
 
 func NewFlagRepository() model.FlagRepository {
+	return NewFlagRepositoryWithLogger(nil)
+}
+
+// NewFlagRepositoryWithLogger is like NewFlagRepository, a panic in a flag added handler is recovered and logged to logger
+func NewFlagRepositoryWithLogger(logger logging.Logger) model.FlagRepository {
 	return &flagRepository{
 		variants: make(map[string]model.Variant),
+		logger:   logging.OrGlobal(logger),
 	}
 }
 
@@ -67,8 +74,8 @@ func (r *flagRepository) raiseFlagAddedEvent(flag model.Variant) {
 	r.handlersMutex.RUnlock()
 
 	defer func() {
-		if r := recover(); r != nil {
-			logging.GetLogger().Error("Failed to execute flag added handler, panic", r)
+		if err := recover(); err != nil {
+			r.logger.Error("Failed to execute flag added handler, panic", err)
 		}
 	}()
 
@@ -0,0 +1,147 @@
+package roxx
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/utils"
+)
+
+// minInt is the int without a positive counterpart, math.MinInt needs go 1.17
+const minInt = -1 << (strconv.IntSize - 1)
+
+// ArithmeticExtensions adds arithmetic operators, operands are coerced with utils.ToFloat.
+// The result is an int when every operand is an int, or a string holding one, and the result is whole.
+// Int results that overflow are computed with floats instead.
+// Operands that are not numbers, division and modulo by zero yield undefined
+type ArithmeticExtensions struct {
+	parser Parser
+}
+
+func NewArithmeticExtensions(parser Parser) *ArithmeticExtensions {
+	return &ArithmeticExtensions{parser: parser}
+}
+
+func (e *ArithmeticExtensions) Extend() {
+	e.addBinary("add", func(a, b int) (int, bool) {
+		result := a + b
+		return result, (result > a) == (b > 0)
+	}, func(a, b float64) (float64, bool) {
+		return a + b, true
+	})
+
+	e.addBinary("sub", func(a, b int) (int, bool) {
+		result := a - b
+		return result, (result < a) == (b > 0)
+	}, func(a, b float64) (float64, bool) {
+		return a - b, true
+	})
+
+	e.addBinary("mul", func(a, b int) (int, bool) {
+		if a == 0 || b == 0 {
+			return 0, true
+		}
+		// the division does not detect minInt * -1, which is minInt again
+		result := a * b
+		return result, result/b == a && !(a == -1 && b == minInt) && !(b == -1 && a == minInt)
+	}, func(a, b float64) (float64, bool) {
+		return a * b, true
+	})
+
+	e.addBinary("div", func(a, b int) (int, bool) {
+		// a fraction is computed with floats, as is minInt / -1, which is minInt again
+		if b == 0 || a%b != 0 {
+			return 0, false
+		}
+		return a / b, !(b == -1 && a == minInt)
+	}, func(a, b float64) (float64, bool) {
+		return a / b, b != 0
+	})
+
+	e.addBinary("mod", func(a, b int) (int, bool) {
+		if b == 0 {
+			return 0, false
+		}
+		return a % b, true
+	}, func(a, b float64) (float64, bool) {
+		return math.Mod(a, b), b != 0
+	})
+
+	e.addBinary("min", func(a, b int) (int, bool) {
+		if a < b {
+			return a, true
+		}
+		return b, true
+	}, func(a, b float64) (float64, bool) {
+		return math.Min(a, b), true
+	})
+
+	e.addBinary("max", func(a, b int) (int, bool) {
+		if a > b {
+			return a, true
+		}
+		return b, true
+	}, func(a, b float64) (float64, bool) {
+		return math.Max(a, b), true
+	})
+
+	e.parser.AddOperatorWithSignature("abs", NewOperatorSignature(OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
+		op1 := stack.Pop()
+
+		if intValue, ok := toInt(op1); ok && intValue != minInt {
+			if intValue < 0 {
+				intValue = -intValue
+			}
+			stack.Push(intValue)
+		} else if number, ok := utils.ToFloat(op1); ok {
+			stack.Push(math.Abs(number))
+		} else {
+			stack.Push(TokenTypeUndefined)
+		}
+	})
+}
+
+// addBinary adds an operator computed with intOperation when both operands are ints and with floatOperation otherwise,
+// an operation that is not ok falls back to floats for ints and yields undefined for floats
+func (e *ArithmeticExtensions) addBinary(name string, intOperation func(a, b int) (int, bool), floatOperation func(a, b float64) (float64, bool)) {
+	e.parser.AddOperatorWithSignature(name, NewOperatorSignature(OperandTypeNumber, OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
+		op1 := stack.Pop()
+		op2 := stack.Pop()
+
+		int1, ok1 := toInt(op1)
+		int2, ok2 := toInt(op2)
+		if ok1 && ok2 {
+			if result, ok := intOperation(int1, int2); ok {
+				stack.Push(result)
+				return
+			}
+		}
+
+		number1, ok1 := utils.ToFloat(op1)
+		number2, ok2 := utils.ToFloat(op2)
+		if !ok1 || !ok2 {
+			stack.Push(TokenTypeUndefined)
+			return
+		}
+
+		result, ok := floatOperation(number1, number2)
+		if !ok || math.IsNaN(result) || math.IsInf(result, 0) {
+			stack.Push(TokenTypeUndefined)
+		} else {
+			stack.Push(result)
+		}
+	})
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case string:
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue, true
+		}
+	}
+	return 0, false
+}
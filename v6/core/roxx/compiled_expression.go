@@ -0,0 +1,203 @@
+package roxx
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/rollout/rox-go/v6/core/context"
+)
+
+// DefaultExpressionCacheSize is the number of compiled expressions a parser keeps
+const DefaultExpressionCacheSize = 10000
+
+// branch pops the operand deciding which instructions run next, it returns true to continue at the instruction's target
+type branch = func(stack *CoreStack) bool
+
+type instruction struct {
+	node      *Node
+	operation Operation
+	// branch and target are set for the short-circuit instructions of and, or and ifThen
+	branch branch
+	target int
+}
+
+// literalOperation compiles an operator called with a literal operand to an operation prepared for that literal,
+// such as one with the literal already parsed. The operation still pops the literal
+type literalOperation struct {
+	operand int
+	compile func(literal interface{}) Operation
+}
+
+// CompiledExpression is an expression tokenized once into evaluation order with its operators resolved,
+// it is never modified after compilation so it can be evaluated concurrently
+type CompiledExpression struct {
+	expression       string
+	instructions     []instruction
+	isValid          bool
+	operatorsVersion uint64
+	// err is why the expression failed to compile, evaluating it fails with it
+	err interface{}
+}
+
+// compileNode is an operator with its operands in argument order, or an operand
+type compileNode struct {
+	token    *Node
+	operands []*compileNode
+}
+
+func (p *roxxParser) compile(expression string) (compiled *CompiledExpression) {
+	operatorsVersion := atomic.LoadUint64(&p.operatorsVersion)
+	defer func() {
+		// expressions come from the configuration, one the tokenizer can not read must not fail the caller
+		if r := recover(); r != nil {
+			compiled = &CompiledExpression{expression: expression, operatorsVersion: operatorsVersion, err: r}
+		}
+	}()
+
+	tokens := NewTokenizedExpression(expression, p.Operators()).GetTokens()
+	p.reverseTokens(tokens)
+
+	compiled = &CompiledExpression{
+		expression:       expression,
+		instructions:     make([]instruction, 0, len(tokens)),
+		isValid:          true,
+		operatorsVersion: operatorsVersion,
+	}
+
+	if root := p.buildTree(tokens); root != nil {
+		p.emit(compiled, root)
+		return compiled
+	}
+
+	// operands can not be told apart without the arity of every operator, the expression is evaluated eagerly
+	for _, token := range tokens {
+		switch token.Type {
+		case NodeTypeRand:
+			compiled.instructions = append(compiled.instructions, instruction{node: token})
+		case NodeTypeRator:
+			operator := token.Value.(string)
+			if _, ok := p.signatures[operator]; !ok {
+				p.logger.Debug(fmt.Sprintf("Evaluating all operands of '%s', %s was added without a signature", expression, operator), nil)
+			}
+			if operation, ok := p.operatorsMap[operator]; ok {
+				compiled.instructions = append(compiled.instructions, instruction{node: token, operation: operation})
+			}
+		default:
+			compiled.isValid = false
+		}
+	}
+	return compiled
+}
+
+// buildTree returns nil unless every operator was added with a signature and the expression is a single well formed value
+func (p *roxxParser) buildTree(tokens []*Node) *compileNode {
+	var stack []*compileNode
+	for _, token := range tokens {
+		switch token.Type {
+		case NodeTypeRand:
+			stack = append(stack, &compileNode{token: token})
+		case NodeTypeRator:
+			signature, ok := p.signatures[token.Value.(string)]
+			arity := len(signature.Operands)
+			if !ok || len(stack) < arity {
+				return nil
+			}
+			node := &compileNode{token: token, operands: make([]*compileNode, arity)}
+			for i := range node.operands {
+				node.operands[i] = stack[len(stack)-1-i]
+			}
+			stack = append(stack[:len(stack)-arity], node)
+		default:
+			return nil
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil
+	}
+	return stack[0]
+}
+
+// emit appends the instructions of node, operands are evaluated from the last to the first like the eager form,
+// except for the operators evaluated lazily
+func (p *roxxParser) emit(compiled *CompiledExpression, node *compileNode) {
+	if node.token.Type == NodeTypeRand {
+		compiled.instructions = append(compiled.instructions, instruction{node: node.token})
+		return
+	}
+
+	operator := node.token.Value.(string)
+	if p.shortCircuitOperators[operator] {
+		switch operator {
+		case "and", "or":
+			p.emitShortCircuit(compiled, node)
+			return
+		case "ifThen":
+			p.emitIfThen(compiled, node)
+			return
+		}
+	}
+
+	for i := len(node.operands) - 1; i >= 0; i-- {
+		p.emit(compiled, node.operands[i])
+	}
+	operation := p.operatorsMap[operator]
+	if literal, ok := p.literalOperations[operator]; ok && node.operands[literal.operand].token.Type == NodeTypeRand {
+		operation = literal.compile(node.operands[literal.operand].token.Value)
+	}
+	compiled.instructions = append(compiled.instructions, instruction{node: node.token, operation: operation})
+}
+
+// emitShortCircuit evaluates the second operand of and or or only when the first one does not decide the result
+func (p *roxxParser) emitShortCircuit(compiled *CompiledExpression, node *compileNode) {
+	decidingValue := node.token.Value.(string) == "or"
+
+	p.emit(compiled, node.operands[0])
+	branchIndex := len(compiled.instructions)
+	compiled.instructions = append(compiled.instructions, instruction{node: node.token, branch: func(stack *CoreStack) bool {
+		value := stack.Pop()
+		if value == TokenTypeUndefined {
+			value = false
+		}
+
+		if value.(bool) == decidingValue {
+			stack.Push(decidingValue)
+			return true
+		}
+		return false
+	}})
+
+	p.emit(compiled, node.operands[1])
+	compiled.instructions = append(compiled.instructions, instruction{node: node.token, operation: func(p Parser, stack *CoreStack, context context.Context) {
+		value := stack.Pop()
+		if value == TokenTypeUndefined {
+			value = false
+		}
+
+		stack.Push(value.(bool))
+	}})
+	compiled.instructions[branchIndex].target = len(compiled.instructions)
+}
+
+// emitIfThen evaluates the condition then only the chosen branch
+func (p *roxxParser) emitIfThen(compiled *CompiledExpression, node *compileNode) {
+	p.emit(compiled, node.operands[0])
+	conditionIndex := len(compiled.instructions)
+	compiled.instructions = append(compiled.instructions, instruction{node: node.token, branch: func(stack *CoreStack) bool {
+		return !stack.Pop().(bool)
+	}})
+
+	p.emit(compiled, node.operands[1])
+	jumpIndex := len(compiled.instructions)
+	compiled.instructions = append(compiled.instructions, instruction{branch: func(stack *CoreStack) bool {
+		return true
+	}})
+
+	compiled.instructions[conditionIndex].target = len(compiled.instructions)
+	p.emit(compiled, node.operands[2])
+	compiled.instructions[jumpIndex].target = len(compiled.instructions)
+}
+
+func (ce *CompiledExpression) Expression() string {
+	return ce.expression
+}
@@ -2,6 +2,8 @@ package roxx
 
 type CoreStack struct {
 	items []interface{}
+	// lowWaterMark is the smallest size of the stack since it was last reset, it tells which items an operation popped
+	lowWaterMark int
 }
 
 func NewCoreStack() *CoreStack {
@@ -15,6 +17,9 @@ func (s *CoreStack) Push(item interface{}) {
 func (s *CoreStack) Pop() interface{} {
 	item := s.items[len(s.items)-1]
 	s.items = s.items[:len(s.items)-1]
+	if len(s.items) < s.lowWaterMark {
+		s.lowWaterMark = len(s.items)
+	}
 	return item
 }
 
@@ -0,0 +1,96 @@
+package roxx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rollout/rox-go/v6/core/context"
+)
+
+// MaxEvaluationDepth is the number of nested expressions, such as target group conditions and flag dependencies,
+// a single evaluation can go through
+const MaxEvaluationDepth = 32
+
+// MaxEvaluationOperations is the number of operands and operators a single evaluation can run, nested expressions included
+const MaxEvaluationOperations = 100000
+
+// EvaluationLimit is the limit an evaluation went over
+type EvaluationLimit string
+
+const (
+	EvaluationLimitDepth      EvaluationLimit = "depth"
+	EvaluationLimitOperations EvaluationLimit = "operations"
+	EvaluationLimitCycle      EvaluationLimit = "cycle"
+)
+
+// EvaluationLimitError is the cause of the EvaluationError of an evaluation that went over a limit or found a cycle.
+// Unlike other failures outside strict evaluation it fails the outermost evaluation, so the flag falls back to its default value
+type EvaluationLimitError struct {
+	Limit EvaluationLimit
+	// Dependencies are the target groups and flags being evaluated, from the outermost one
+	Dependencies []string
+}
+
+func (e *EvaluationLimitError) Error() string {
+	var message string
+	switch e.Limit {
+	case EvaluationLimitCycle:
+		return fmt.Sprintf("cycle in %s", strings.Join(e.Dependencies, " -> "))
+	case EvaluationLimitDepth:
+		message = fmt.Sprintf("more than %d nested expressions", MaxEvaluationDepth)
+	default:
+		message = fmt.Sprintf("more than %d operations", MaxEvaluationOperations)
+	}
+	if len(e.Dependencies) > 0 {
+		message = fmt.Sprintf("%s in %s", message, strings.Join(e.Dependencies, " -> "))
+	}
+	return message
+}
+
+// evaluationBudgetKey is the context key the budget of the running evaluation is found with, through merged contexts too.
+// Its type keeps the budget out of the user's keys
+type evaluationBudgetKey struct{}
+
+// evaluationBudget is shared by an evaluation and the expressions it evaluates
+type evaluationBudget struct {
+	depth        int
+	operations   int
+	dependencies []string
+}
+
+func (b *evaluationBudget) limitError(limit EvaluationLimit, dependencies ...string) *EvaluationLimitError {
+	return &EvaluationLimitError{Limit: limit, Dependencies: append(append([]string(nil), b.dependencies...), dependencies...)}
+}
+
+func evaluationBudgetOf(ctx context.Context) *evaluationBudget {
+	budget, _ := context.Value(ctx, evaluationBudgetKey{}).(*evaluationBudget)
+	return budget
+}
+
+// withEvaluationBudget returns the budget of the running evaluation, or a context carrying a new one
+func withEvaluationBudget(ctx context.Context) (context.Context, *evaluationBudget) {
+	if budget := evaluationBudgetOf(ctx); budget != nil {
+		return ctx, budget
+	}
+	budget := &evaluationBudget{}
+	return context.NewValueContext(ctx, evaluationBudgetKey{}, budget), budget
+}
+
+// EnterDependency records that the running operation evaluates dependency, such as a target group, until the returned
+// function is called. The evaluation fails with an EvaluationLimitError when dependency is already being evaluated
+func EnterDependency(ctx context.Context, dependency string) (exit func()) {
+	budget := evaluationBudgetOf(ctx)
+	if budget == nil {
+		return func() {}
+	}
+
+	for _, entered := range budget.dependencies {
+		if entered == dependency {
+			panic(budget.limitError(EvaluationLimitCycle, dependency))
+		}
+	}
+	budget.dependencies = append(budget.dependencies, dependency)
+	return func() {
+		budget.dependencies = budget.dependencies[:len(budget.dependencies)-1]
+	}
+}
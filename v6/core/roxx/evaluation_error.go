@@ -0,0 +1,36 @@
+package roxx
+
+import (
+	"fmt"
+)
+
+// EvaluationError is the failure of an expression, typically an operator given an operand of the wrong type
+type EvaluationError struct {
+	Expression string
+	// Operator is the operator that failed, it is empty when the expression did not leave a value
+	Operator string
+	Cause    error
+}
+
+func (e *EvaluationError) Error() string {
+	if e.Operator == "" {
+		return fmt.Sprintf("failed to evaluate '%s': %v", e.Expression, e.Cause)
+	}
+	return fmt.Sprintf("failed to evaluate '%s': %s: %v", e.Expression, e.Operator, e.Cause)
+}
+
+func (e *EvaluationError) Unwrap() error {
+	return e.Cause
+}
+
+// EvaluationErrorHandler is called with every expression that fails, including the ones evaluated by operators
+// such as target group conditions
+type EvaluationErrorHandler = func(err *EvaluationError)
+
+func newEvaluationError(expression, operator string, recovered interface{}) *EvaluationError {
+	cause, ok := recovered.(error)
+	if !ok {
+		cause = fmt.Errorf("%v", recovered)
+	}
+	return &EvaluationError{Expression: expression, Operator: operator, Cause: cause}
+}
@@ -7,16 +7,27 @@ import (
 
 type EvaluationResult struct {
 	value interface{}
+	err   *EvaluationError
 }
 
 func NewEvaluationResult(value interface{}) EvaluationResult {
 	return EvaluationResult{value: value}
 }
 
+// NewEvaluationErrorResult is the undefined result of an expression that failed
+func NewEvaluationErrorResult(err *EvaluationError) EvaluationResult {
+	return EvaluationResult{err: err}
+}
+
 func (ev EvaluationResult) Value() interface{} {
 	return ev.value
 }
 
+// Err is the reason the expression failed, it is nil when the evaluation succeeded
+func (ev EvaluationResult) Err() *EvaluationError {
+	return ev.err
+}
+
 func (ev EvaluationResult) BoolValue() bool {
 	if ev.value == nil {
 		return false
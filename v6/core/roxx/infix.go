@@ -0,0 +1,229 @@
+package roxx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// expressionNode is an expression tree, literals keep their roxx source
+type expressionNode struct {
+	operator string
+	operands []*expressionNode
+	literal  string
+}
+
+func newOperatorNode(operator string, operands ...*expressionNode) *expressionNode {
+	return &expressionNode{operator: operator, operands: operands}
+}
+
+func newLiteralNode(literal string) *expressionNode {
+	return &expressionNode{literal: literal}
+}
+
+// CompileInfix compiles an infix expression such as property("tier") == "gold" && percentage(seed) <= 0.2 to roxx.
+// Besides ! && || == != < <= > >= in and ?: every operator of parser added with a signature can be called as a function,
+// percentage(seed) can only be compared with <=, the bound is included like in isInPercentage, and compiles to it
+func CompileInfix(parser Parser, source string) (string, error) {
+	tokens, err := tokenizeInfix(source)
+	if err != nil {
+		return "", err
+	}
+
+	infix := &infixParser{tokens: tokens, parser: parser}
+	node, err := infix.parseTernary()
+	if err != nil {
+		return "", err
+	}
+	if token := infix.peek(); token.kind != infixTokenEnd {
+		return "", fmt.Errorf("unexpected %s", token)
+	}
+	return node.roxx()
+}
+
+// FormatInfix prints a roxx expression using the operators of parser in the syntax CompileInfix reads
+func FormatInfix(parser Parser, expression string) (string, error) {
+	node, err := parseRoxxTree(parser, expression)
+	if err != nil {
+		return "", err
+	}
+	return node.infix(infixPrecedenceTernary), nil
+}
+
+func (n *expressionNode) roxx() (string, error) {
+	if n.operator == "" {
+		return n.literal, nil
+	}
+	if n.operator == percentageFunction {
+		return "", fmt.Errorf("percentage can only be compared with '<='")
+	}
+
+	operands := make([]string, len(n.operands))
+	for i, operand := range n.operands {
+		compiled, err := operand.roxx()
+		if err != nil {
+			return "", err
+		}
+		operands[i] = compiled
+	}
+	return fmt.Sprintf("%s(%s)", n.operator, strings.Join(operands, ", ")), nil
+}
+
+const (
+	infixPrecedenceTernary = iota
+	infixPrecedenceOr
+	infixPrecedenceAnd
+	infixPrecedenceEquality
+	infixPrecedenceComparison
+	infixPrecedenceUnary
+	infixPrecedencePrimary
+)
+
+var infixOperators = map[string]struct {
+	symbol     string
+	precedence int
+}{
+	"or":      {"||", infixPrecedenceOr},
+	"and":     {"&&", infixPrecedenceAnd},
+	"eq":      {"==", infixPrecedenceEquality},
+	"ne":      {"!=", infixPrecedenceEquality},
+	"lt":      {"<", infixPrecedenceComparison},
+	"lte":     {"<=", infixPrecedenceComparison},
+	"gt":      {">", infixPrecedenceComparison},
+	"gte":     {">=", infixPrecedenceComparison},
+	"inArray": {"in", infixPrecedenceComparison},
+}
+
+// infix prints the node, in parentheses when it binds looser than the precedence its position requires
+func (n *expressionNode) infix(precedence int) string {
+	text, nodePrecedence := n.infixText()
+	if nodePrecedence < precedence {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+func (n *expressionNode) infixText() (string, int) {
+	if n.operator == "" {
+		return n.literal, infixPrecedencePrimary
+	}
+
+	switch n.operator {
+	case "ifThen":
+		return fmt.Sprintf("%s ? %s : %s",
+			n.operands[0].infix(infixPrecedenceOr),
+			n.operands[1].infix(infixPrecedenceTernary),
+			n.operands[2].infix(infixPrecedenceTernary)), infixPrecedenceTernary
+	case "not":
+		return "!" + n.operands[0].infix(infixPrecedenceUnary), infixPrecedenceUnary
+	case "isInPercentage":
+		return fmt.Sprintf("%s(%s) <= %s",
+			percentageFunction,
+			n.operands[1].infix(infixPrecedenceTernary),
+			n.operands[0].infix(infixPrecedenceUnary)), infixPrecedenceComparison
+	}
+
+	if operator, ok := infixOperators[n.operator]; ok {
+		if operator.precedence == infixPrecedenceComparison {
+			// comparisons do not chain, both sides need to bind tighter
+			return fmt.Sprintf("%s %s %s",
+				n.operands[0].infix(infixPrecedenceUnary),
+				operator.symbol,
+				n.operands[1].infix(infixPrecedenceUnary)), operator.precedence
+		}
+		return fmt.Sprintf("%s %s %s",
+			n.operands[0].infix(operator.precedence),
+			operator.symbol,
+			n.operands[1].infix(operator.precedence+1)), operator.precedence
+	}
+
+	operands := make([]string, len(n.operands))
+	for i, operand := range n.operands {
+		operands[i] = operand.infix(infixPrecedenceTernary)
+	}
+	return fmt.Sprintf("%s(%s)", n.operator, strings.Join(operands, ", ")), infixPrecedencePrimary
+}
+
+// parseRoxxTree builds the tree of a roxx expression using the operator signatures of parser
+func parseRoxxTree(parser Parser, expression string) (node *expressionNode, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to parse '%s': %v", expression, r)
+		}
+	}()
+
+	tokenizedExpression := NewTokenizedExpression(expression, parser.Operators())
+	tokens := tokenizedExpression.GetTokens()
+	if len(tokenizedExpression.problems) > 0 {
+		return nil, fmt.Errorf("%s in '%s'", tokenizedExpression.problems[0], expression)
+	}
+
+	var stack []*expressionNode
+	for i := len(tokens) - 1; i >= 0; i-- {
+		token := tokens[i]
+		switch token.Type {
+		case NodeTypeRand:
+			stack = append(stack, newLiteralNode(roxxLiteral(token.Value)))
+		case NodeTypeRator:
+			operator := token.Value.(string)
+			signature, ok := parser.OperatorSignature(operator)
+			if !ok {
+				return nil, fmt.Errorf("operator %s was added without a signature in '%s'", operator, expression)
+			}
+			arity := len(signature.Operands)
+			if len(stack) < arity {
+				return nil, fmt.Errorf("%s expects %d operands, got %d in '%s'", operator, arity, len(stack), expression)
+			}
+			operands := make([]*expressionNode, arity)
+			for j := range operands {
+				operands[j] = stack[len(stack)-1-j]
+			}
+			stack = append(stack[:len(stack)-arity], newOperatorNode(operator, operands...))
+		default:
+			return nil, fmt.Errorf("unknown operator %v in '%s'", token.Value, expression)
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("expected a single value, got %d in '%s'", len(stack), expression)
+	}
+	return stack[0], nil
+}
+
+// roxxLiteral prints a value read by the roxx tokenizer so that it is read back the same
+func roxxLiteral(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return `"` + value + `"`
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		text := strconv.FormatFloat(value, 'f', -1, 64)
+		if !strings.Contains(text, ".") {
+			// keep the value a float, eq compares ints and floats as different values
+			text += ".0"
+		}
+		return text
+	case bool:
+		return strconv.FormatBool(value)
+	case []interface{}:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = roxxLiteral(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = fmt.Sprintf(`"%s": %s`, key, roxxLiteral(value[key]))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return roxxUndefined
+}
@@ -0,0 +1,347 @@
+package roxx
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// percentageFunction is the infix form of isInPercentage, percentage(seed) <= 0.2 compiles to isInPercentage(0.2, seed)
+const percentageFunction = "percentage"
+
+type infixTokenKind int
+
+const (
+	infixTokenEnd infixTokenKind = iota
+	infixTokenIdentifier
+	infixTokenString
+	infixTokenNumber
+	infixTokenPunctuation
+)
+
+type infixToken struct {
+	kind     infixTokenKind
+	text     string
+	position int
+}
+
+func (t infixToken) String() string {
+	if t.kind == infixTokenEnd {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s' at %d", t.text, t.position)
+}
+
+// infix punctuation, longer tokens first so that <= is not read as <
+var infixPunctuation = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "?", ":", "(", ")", "[", "]", "{", "}", ","}
+
+func tokenizeInfix(source string) ([]infixToken, error) {
+	var tokens []infixToken
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			tokens = append(tokens, infixToken{kind: infixTokenString, text: string(runes[start:i]), position: start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, infixToken{kind: infixTokenNumber, text: string(runes[start:i]), position: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, infixToken{kind: infixTokenIdentifier, text: string(runes[start:i]), position: start})
+		default:
+			matched := false
+			for _, punctuation := range infixPunctuation {
+				if strings.HasPrefix(string(runes[i:]), punctuation) {
+					tokens = append(tokens, infixToken{kind: infixTokenPunctuation, text: punctuation, position: i})
+					i += len([]rune(punctuation))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character '%c' at %d", r, i)
+			}
+		}
+	}
+	return append(tokens, infixToken{kind: infixTokenEnd, position: len(runes)}), nil
+}
+
+// infixParser is a recursive descent parser, from the lowest precedence:
+// ternary ?:, ||, &&, == and !=, comparisons and in, unary !, then literals and function calls
+type infixParser struct {
+	tokens  []infixToken
+	current int
+	// parser has the operators functions are compiled to
+	parser Parser
+}
+
+func (p *infixParser) peek() infixToken {
+	return p.tokens[p.current]
+}
+
+func (p *infixParser) next() infixToken {
+	token := p.tokens[p.current]
+	if token.kind != infixTokenEnd {
+		p.current++
+	}
+	return token
+}
+
+func (p *infixParser) accept(punctuation string) bool {
+	if token := p.peek(); token.kind == infixTokenPunctuation && token.text == punctuation {
+		p.current++
+		return true
+	}
+	return false
+}
+
+func (p *infixParser) expect(punctuation string) error {
+	if !p.accept(punctuation) {
+		return fmt.Errorf("expected '%s', got %s", punctuation, p.peek())
+	}
+	return nil
+}
+
+func (p *infixParser) parseTernary() (*expressionNode, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil || !p.accept("?") {
+		return condition, err
+	}
+
+	whenTrue, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	whenFalse, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return newOperatorNode("ifThen", condition, whenTrue, whenFalse), nil
+}
+
+// infixBinaryLevels are the left associative binary operators by increasing precedence
+var infixBinaryLevels = []map[string]string{
+	{"||": "or"},
+	{"&&": "and"},
+	{"==": "eq", "!=": "ne"},
+}
+
+func (p *infixParser) parseBinary(level int) (*expressionNode, error) {
+	if level == len(infixBinaryLevels) {
+		return p.parseComparison()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		token := p.peek()
+		operator, ok := infixBinaryLevels[level][token.text]
+		if token.kind != infixTokenPunctuation || !ok {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = newOperatorNode(operator, left, right)
+	}
+}
+
+var infixComparisons = map[string]string{"<": "lt", "<=": "lte", ">": "gt", ">=": "gte", "in": "inArray"}
+
+func (p *infixParser) parseComparison() (*expressionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	token := p.peek()
+	operator, ok := infixComparisons[token.text]
+	if !ok || (token.kind != infixTokenPunctuation && token.kind != infixTokenIdentifier) {
+		return left, nil
+	}
+	p.next()
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	if left.operator == percentageFunction {
+		if operator != "lte" {
+			return nil, fmt.Errorf("percentage can only be compared with '<=', got %s", token)
+		}
+		return newOperatorNode("isInPercentage", right, left.operands[0]), nil
+	}
+	return newOperatorNode(operator, left, right), nil
+}
+
+func (p *infixParser) parseUnary() (*expressionNode, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return newOperatorNode("not", operand), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *infixParser) parsePrimary() (*expressionNode, error) {
+	token := p.next()
+	switch token.kind {
+	case infixTokenString, infixTokenNumber:
+		return newLiteralNode(token.text), nil
+	case infixTokenIdentifier:
+		if token.text == roxxTrue || token.text == roxxFalse || token.text == roxxUndefined {
+			return newLiteralNode(token.text), nil
+		}
+		return p.parseCall(token)
+	case infixTokenPunctuation:
+		switch token.text {
+		case "(":
+			node, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expect(")")
+		case "[", "{":
+			p.current--
+			literal, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			return newLiteralNode(literal), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s", token)
+}
+
+func (p *infixParser) parseCall(name infixToken) (*expressionNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, fmt.Errorf("unknown identifier %s", name)
+	}
+
+	var operands []*expressionNode
+	if !p.accept(")") {
+		for {
+			operand, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, operand)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	arity := 1
+	if name.text != percentageFunction {
+		signature, ok := p.parser.OperatorSignature(name.text)
+		if !ok {
+			if hasOperator(p.parser, name.text) {
+				return nil, fmt.Errorf("function %s was added without a signature", name)
+			}
+			return nil, fmt.Errorf("unknown function %s", name)
+		}
+		arity = len(signature.Operands)
+	}
+	if len(operands) != arity {
+		return nil, fmt.Errorf("function %s expects %d arguments, got %d", name, arity, len(operands))
+	}
+	return newOperatorNode(name.text, operands...), nil
+}
+
+// parseLiteral reads a literal in roxx syntax, arrays and dictionaries can only hold literals
+func (p *infixParser) parseLiteral() (string, error) {
+	token := p.next()
+	switch token.kind {
+	case infixTokenString, infixTokenNumber:
+		return token.text, nil
+	case infixTokenIdentifier:
+		if token.text == roxxTrue || token.text == roxxFalse || token.text == roxxUndefined {
+			return token.text, nil
+		}
+	case infixTokenPunctuation:
+		switch token.text {
+		case "[":
+			var items []string
+			for !p.accept("]") {
+				if len(items) > 0 {
+					if err := p.expect(","); err != nil {
+						return "", err
+					}
+				}
+				item, err := p.parseLiteral()
+				if err != nil {
+					return "", err
+				}
+				items = append(items, item)
+			}
+			return "[" + strings.Join(items, ", ") + "]", nil
+		case "{":
+			var items []string
+			for !p.accept("}") {
+				if len(items) > 0 {
+					if err := p.expect(","); err != nil {
+						return "", err
+					}
+				}
+				key := p.next()
+				if key.kind != infixTokenString {
+					return "", fmt.Errorf("expected a string key, got %s", key)
+				}
+				if err := p.expect(":"); err != nil {
+					return "", err
+				}
+				value, err := p.parseLiteral()
+				if err != nil {
+					return "", err
+				}
+				items = append(items, key.text+": "+value)
+			}
+			return "{" + strings.Join(items, ", ") + "}", nil
+		}
+	}
+	return "", fmt.Errorf("expected a literal, got %s", token)
+}
+
+func hasOperator(parser Parser, name string) bool {
+	for _, operator := range parser.Operators() {
+		if operator == name {
+			return true
+		}
+	}
+	return false
+}
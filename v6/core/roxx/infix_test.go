@@ -0,0 +1,118 @@
+package roxx_test
+
+import (
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/roxx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileInfixWillCompileOperators(t *testing.T) {
+	parser := newExpressionParser()
+
+	for source, expected := range map[string]string{
+		`property("tier") == "gold" && percentage(mergeSeed("exp", property("id"))) <= 0.2`: `and(eq(property("tier"), "gold"), isInPercentage(0.2, mergeSeed("exp", property("id"))))`,
+		`true || false && !true`:                          `or(true, and(false, not(true)))`,
+		`(true || false) && true`:                         `and(or(true, false), true)`,
+		`1 < 2 != 3 >= -4.5`:                              `ne(lt(1, 2), gte(3, -4.5))`,
+		`isInTargetGroup("tg") ? "yes" : "no"`:            `ifThen(isInTargetGroup("tg"), "yes", "no")`,
+		`true ? 1 : false ? 2 : 3`:                        `ifThen(true, 1, ifThen(false, 2, 3))`,
+		`"a" in ["a", 1, true]`:                           `inArray("a", ["a", 1, true])`,
+		`semverGte(property("version"), "1.2.3") == true`: `eq(semverGte(property("version"), "1.2.3"), true)`,
+		`isUndefined(flagValue("flag")) || now() > 0`:     `or(isUndefined(flagValue("flag")), gt(now(), 0))`,
+		`match("a\"b", "a.b", "")`:                        `match("a\"b", "a.b", "")`,
+	} {
+		compiled, err := roxx.CompileInfix(parser, source)
+
+		assert.NoError(t, err, source)
+		assert.Equal(t, expected, compiled, source)
+		assert.Empty(t, roxx.Validate(parser, compiled), source)
+	}
+}
+
+func TestCompileInfixWillReportErrors(t *testing.T) {
+	parser := newExpressionParser()
+
+	for source, message := range map[string]string{
+		`unknown("a")`:             "unknown function 'unknown' at 0",
+		`tier == "gold"`:           "unknown identifier 'tier' at 0",
+		`md5("a", "b")`:            "function 'md5' at 0 expects 1 arguments, got 2",
+		`percentage("seed") < 0.5`: "percentage can only be compared with '<=', got '<' at 19",
+		`percentage("seed")`:       "percentage can only be compared with '<='",
+		`true &&`:                  "unexpected end of expression",
+		`(true`:                    "expected ')', got end of expression",
+		`"abc`:                     "unterminated string at 0",
+		`true false`:               "unexpected 'false' at 5",
+		`"a" in [property("a")]`:   "expected a literal, got 'property' at 8",
+		`true # false`:             "unexpected character '#' at 5",
+	} {
+		_, err := roxx.CompileInfix(parser, source)
+
+		assert.EqualError(t, err, message, source)
+	}
+}
+
+func TestCompileInfixWillReportOperatorsWithoutSignature(t *testing.T) {
+	parser := roxx.NewParser()
+	parser.AddOperator("custom", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		stack.Push(true)
+	})
+
+	_, err := roxx.CompileInfix(parser, `custom("a")`)
+	assert.EqualError(t, err, "function 'custom' at 0 was added without a signature")
+
+	_, err = roxx.FormatInfix(parser, `custom("a")`)
+	assert.EqualError(t, err, "operator custom was added without a signature in 'custom(\"a\")'")
+}
+
+func TestFormatInfixWillPrintRoxxExpressions(t *testing.T) {
+	parser := newExpressionParser()
+
+	for expression, expected := range map[string]string{
+		`and(eq(property("tier"), "gold"), isInPercentage(0.2, mergeSeed("exp", property("id"))))`: `property("tier") == "gold" && percentage(mergeSeed("exp", property("id"))) <= 0.2`,
+		`and(or(true, false), not(and(true, false)))`:                                              `(true || false) && !(true && false)`,
+		`or(true, or(false, true))`:                                                                `true || (false || true)`,
+		`ifThen(ifThen(true, false, true), ifThen(true, 1, 2), 3.0)`:                               `(true ? false : true) ? true ? 1 : 2 : 3.0`,
+		`inArray(property("country"), ["us", "uk"])`:                                               `property("country") in ["us", "uk"]`,
+		`eq(undefined, isInTargetGroup("tg"))`:                                                     `undefined == isInTargetGroup("tg")`,
+		`lt(lt(1, 2), 3)`:                                                                          `(1 < 2) < 3`,
+	} {
+		formatted, err := roxx.FormatInfix(parser, expression)
+
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, formatted, expression)
+	}
+}
+
+func TestFormatInfixWillRoundTrip(t *testing.T) {
+	parser := newExpressionParser()
+
+	for _, expression := range []string{
+		`and(eq(property("tier"), "gold"), isInPercentage(0.2, mergeSeed("exp", property("id"))))`,
+		`ifThen(and(true, false), ifThen(true, "a", "b"), or(false, not(eq(1, 1.5))))`,
+		`isInPercentageRange(0.1, 0.5, md5(concat("a", b64d("Yg=="))))`,
+		`or(semverLt(property("v"), "1.0.0"), match("x", "[a-z]+", "i"))`,
+		`and(numeq(tsToNum(property("t")), 1), numneq(2, lte(1, 2)))`,
+	} {
+		formatted, err := roxx.FormatInfix(parser, expression)
+		assert.NoError(t, err, expression)
+
+		compiled, err := roxx.CompileInfix(parser, formatted)
+		assert.NoError(t, err, formatted)
+		assert.Equal(t, expression, compiled)
+	}
+}
+
+func TestFormatInfixWillReportInvalidExpressions(t *testing.T) {
+	parser := newExpressionParser()
+
+	_, err := roxx.FormatInfix(parser, `and(true)`)
+	assert.EqualError(t, err, "and expects 2 operands, got 1 in 'and(true)'")
+
+	_, err = roxx.FormatInfix(parser, `unknown("a")`)
+	assert.EqualError(t, err, "unknown operator unknown in 'unknown(\"a\")'")
+
+	_, err = roxx.FormatInfix(parser, `inArray("a", ["a"`)
+	assert.EqualError(t, err, "unbalanced '[' in 'inArray(\"a\", [\"a\"'")
+}
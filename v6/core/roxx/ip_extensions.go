@@ -0,0 +1,118 @@
+package roxx
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/rollout/rox-go/v6/core/context"
+)
+
+// IPExtensions adds network operators for IPv4 and IPv6 addresses
+type IPExtensions struct {
+	parser Parser
+}
+
+func NewIPExtensions(parser Parser) *IPExtensions {
+	return &IPExtensions{parser: parser}
+}
+
+func (e *IPExtensions) Extend() {
+	// ipInCidr is true when the address is in one of the CIDR ranges, given as an array or a single string.
+	// A range without a prefix length matches a single address, a range that fails to parse fails the evaluation
+	e.parser.AddOperatorWithSignature("ipInCidr", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeAny), ipInCidr(parseNetworks))
+
+	// built in parsers parse literal ranges once, when the expression is compiled
+	if parser, ok := e.parser.(*roxxParser); ok {
+		parser.literalOperations["ipInCidr"] = literalOperation{operand: 1, compile: func(literal interface{}) Operation {
+			networks := parseNetworks(literal)
+			return ipInCidr(func(interface{}) *networkList {
+				return networks
+			})
+		}}
+	}
+}
+
+func ipInCidr(networksOf func(value interface{}) *networkList) Operation {
+	return func(p Parser, stack *CoreStack, context context.Context) {
+		ip, ok := toIP(stack.Pop())
+		networks := networksOf(stack.Pop())
+
+		if networks != nil && networks.err != nil {
+			panic(networks.err)
+		}
+		if !ok || networks == nil {
+			stack.Push(false)
+			return
+		}
+
+		for _, network := range networks.networks {
+			if network.Contains(ip) {
+				stack.Push(true)
+				return
+			}
+		}
+		stack.Push(false)
+	}
+}
+
+// networkList holds the parsed ranges of an ipInCidr operand, or the error of the first range that failed to parse
+type networkList struct {
+	networks []*net.IPNet
+	err      error
+}
+
+// parseNetworks returns nil when value is neither a range nor a list of ranges
+func parseNetworks(value interface{}) *networkList {
+	var ranges []interface{}
+	if str, ok := value.(string); ok {
+		ranges = []interface{}{str}
+	} else if list, ok := toList(value); ok {
+		ranges = list
+	} else {
+		return nil
+	}
+
+	networks := &networkList{networks: make([]*net.IPNet, 0, len(ranges))}
+	for _, item := range ranges {
+		network, err := parseNetwork(item)
+		if err != nil {
+			return &networkList{err: err}
+		}
+		networks.networks = append(networks.networks, network)
+	}
+	return networks
+}
+
+func parseNetwork(value interface{}) (*net.IPNet, error) {
+	cidr, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("CIDR range %v is not a string", value)
+	}
+
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid CIDR range %s", cidr)
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR range %s", cidr)
+	}
+	return network, nil
+}
+
+// toIP accepts net.IP values, as pushed by IP properties, and strings
+func toIP(value interface{}) (net.IP, bool) {
+	switch value := value.(type) {
+	case net.IP:
+		return value, value != nil
+	case string:
+		ip := net.ParseIP(strings.TrimSpace(value))
+		return ip, ip != nil
+	}
+	return nil, false
+}
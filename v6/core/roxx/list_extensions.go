@@ -0,0 +1,84 @@
+package roxx
+
+import (
+	"github.com/rollout/rox-go/v6/core/context"
+)
+
+// ListExtensions adds set operators on arrays, such as string list properties.
+// Like inArray, items are compared by value and operands that are not arrays make the result false
+type ListExtensions struct {
+	parser Parser
+}
+
+func NewListExtensions(parser Parser) *ListExtensions {
+	return &ListExtensions{parser: parser}
+}
+
+func (e *ListExtensions) Extend() {
+	// intersects is true when the lists have an item in common
+	e.addSetOperator("intersects", func(list1, list2 []interface{}) bool {
+		for _, item := range list1 {
+			if listContains(list2, item) {
+				return true
+			}
+		}
+		return false
+	})
+
+	// containsAll is true when every item of the second list is in the first
+	e.addSetOperator("containsAll", func(list1, list2 []interface{}) bool {
+		for _, item := range list2 {
+			if !listContains(list1, item) {
+				return false
+			}
+		}
+		return true
+	})
+
+	// isSubsetOf is true when every item of the first list is in the second
+	e.addSetOperator("isSubsetOf", func(list1, list2 []interface{}) bool {
+		for _, item := range list1 {
+			if !listContains(list2, item) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (e *ListExtensions) addSetOperator(name string, operation func(list1, list2 []interface{}) bool) {
+	e.parser.AddOperatorWithSignature(name, NewOperatorSignature(OperandTypeBoolean, OperandTypeArray, OperandTypeArray), func(p Parser, stack *CoreStack, context context.Context) {
+		list1, ok1 := toList(stack.Pop())
+		list2, ok2 := toList(stack.Pop())
+
+		if !ok1 || !ok2 {
+			stack.Push(false)
+		} else {
+			stack.Push(operation(list1, list2))
+		}
+	})
+}
+
+// toList accepts []string as well, as returned by dynamic property rule handlers
+func toList(value interface{}) ([]interface{}, bool) {
+	switch value := value.(type) {
+	case []interface{}:
+		return value, true
+	case []string:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = item
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func listContains(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
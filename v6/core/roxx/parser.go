@@ -5,76 +5,266 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/rollout/rox-go/v6/core/context"
 	"github.com/rollout/rox-go/v6/core/logging"
+	"github.com/rollout/rox-go/v6/core/utils"
 )
 
 type Parser interface {
 	EvaluateExpression(expression string, context context.Context) EvaluationResult
+	CompileExpression(expression string) *CompiledExpression
+	EvaluateCompiledExpression(compiled *CompiledExpression, context context.Context) EvaluationResult
+	ExplainExpression(expression string, context context.Context) (EvaluationResult, *ExpressionTrace)
 	AddOperator(name string, operation Operation)
+	// AddOperatorWithSignature adds an operator along with the operands it pops and the value it pushes,
+	// which Validate checks expressions against and the compiler needs to evaluate operands lazily
+	AddOperatorWithSignature(name string, signature OperatorSignature, operation Operation)
+	// Operators returns the names of the added operators
+	Operators() []string
+	// OperatorSignature returns the signature an operator was added with, ok is false for operators added without one
+	OperatorSignature(name string) (signature OperatorSignature, ok bool)
+	SetEvaluationErrorHandler(handler EvaluationErrorHandler)
+	// SetStrictEvaluation makes the failure of a nested expression, such as a target group condition, fail the
+	// outermost evaluation instead of leaving an undefined value to the operator that evaluated it
+	SetStrictEvaluation(strict bool)
 }
 
 type Operation = func(p Parser, stack *CoreStack, context context.Context)
 
 type roxxParser struct {
-	operatorsMap map[string]Operation
+	// operatorsVersion changes with every added operator, expressions compiled with an older version are compiled again.
+	// It is the first field so that it is 64-bit aligned for atomic access on 32-bit platforms
+	operatorsVersion uint64
+	operatorsMap     map[string]Operation
+	signatures       map[string]OperatorSignature
+	// shortCircuitOperators are the built in operators compiled to evaluate only the operands they need
+	shortCircuitOperators map[string]bool
+	// literalOperations are the built in operators compiled to operations prepared for their literal operand
+	literalOperations map[string]literalOperation
+	expressionCache   *utils.LRUCache
+	logger            logging.Logger
+	// errorHandler holds an evaluationErrorHandlerHolder, it can be set while the parser is in use
+	errorHandler atomic.Value
+	// strictEvaluation is 1 in strict evaluation mode, it can be set while the parser is in use
+	strictEvaluation uint32
+}
+
+type evaluationErrorHandlerHolder struct {
+	handler EvaluationErrorHandler
 }
 
 func NewParser() Parser {
+	return NewParserWithLogger(nil)
+}
+
+// NewParserWithLogger is like NewParser, expressions that fail to evaluate are logged to logger as warnings
+// and a panicking evaluation error handler as an error
+func NewParserWithLogger(logger logging.Logger) Parser {
 	p := &roxxParser{
-		operatorsMap: make(map[string]Operation),
+		operatorsMap:          make(map[string]Operation),
+		signatures:            make(map[string]OperatorSignature),
+		shortCircuitOperators: make(map[string]bool),
+		literalOperations:     make(map[string]literalOperation),
+		expressionCache:       utils.NewLRUCache(DefaultExpressionCacheSize),
+		logger:                logging.OrGlobal(logger),
 	}
 	p.setBasicOperators()
 	NewValueCompareExtensions(p).Extend()
 	NewRegularExpressionExtensions(p).Extend()
+	NewStringExtensions(p).Extend()
+	NewTimeExtensions(p).Extend()
+	NewArithmeticExtensions(p).Extend()
+	NewListExtensions(p).Extend()
+	NewIPExtensions(p).Extend()
 	return p
 }
 
 func (p *roxxParser) AddOperator(name string, operation Operation) {
+	p.addOperator(name, nil, operation)
+}
+
+func (p *roxxParser) AddOperatorWithSignature(name string, signature OperatorSignature, operation Operation) {
+	p.addOperator(name, &signature, operation)
+}
+
+func (p *roxxParser) addOperator(name string, signature *OperatorSignature, operation Operation) {
 	p.operatorsMap[name] = operation
+	if signature != nil {
+		p.signatures[name] = *signature
+	} else {
+		// the arity of the operator being replaced says nothing about the new one
+		delete(p.signatures, name)
+	}
+	// an operator replacing a built in one is applied to its evaluated operands
+	delete(p.shortCircuitOperators, name)
+	delete(p.literalOperations, name)
+	// operators are resolved at compile time, so expressions compiled before must be compiled again
+	atomic.AddUint64(&p.operatorsVersion, 1)
+	p.expressionCache.Clear()
 }
 
-func (p *roxxParser) EvaluateExpression(expression string, context context.Context) EvaluationResult {
+func (p *roxxParser) Operators() []string {
 	operators := make([]string, 0, len(p.operatorsMap))
 	for operator := range p.operatorsMap {
 		operators = append(operators, operator)
 	}
+	return operators
+}
+
+func (p *roxxParser) OperatorSignature(name string) (OperatorSignature, bool) {
+	signature, ok := p.signatures[name]
+	return signature, ok
+}
+
+// SetEvaluationErrorHandler sets the handler called with the expressions that fail, nil removes it
+func (p *roxxParser) SetEvaluationErrorHandler(handler EvaluationErrorHandler) {
+	p.errorHandler.Store(evaluationErrorHandlerHolder{handler: handler})
+}
+
+func (p *roxxParser) SetStrictEvaluation(strict bool) {
+	var value uint32
+	if strict {
+		value = 1
+	}
+	atomic.StoreUint32(&p.strictEvaluation, value)
+}
+
+func (p *roxxParser) EvaluateExpression(expression string, context context.Context) EvaluationResult {
+	return p.EvaluateCompiledExpression(p.CompileExpression(expression), context)
+}
+
+// CompileExpression returns the compiled form of expression, compiled expressions are cached
+func (p *roxxParser) CompileExpression(expression string) *CompiledExpression {
+	if compiled, ok := p.expressionCache.Get(expression); ok {
+		return compiled.(*CompiledExpression)
+	}
+
+	compiled := p.compile(expression)
+	p.expressionCache.Add(expression, compiled)
+	return compiled
+}
+
+func (p *roxxParser) EvaluateCompiledExpression(compiled *CompiledExpression, context context.Context) EvaluationResult {
+	if compiled.operatorsVersion != atomic.LoadUint64(&p.operatorsVersion) {
+		compiled = p.CompileExpression(compiled.expression)
+	}
+	return p.evaluate(compiled, context, nil, nil)
+}
 
-	tokens := NewTokenizedExpression(expression, operators).GetTokens()
-	p.reverseTokens(tokens)
+// ExplainExpression evaluates expression like EvaluateExpression and records every operation applied on the way
+func (p *roxxParser) ExplainExpression(expression string, context context.Context) (EvaluationResult, *ExpressionTrace) {
+	return newTracingParser(p).explain(expression, context)
+}
 
+// evaluate runs compiled, operations are recorded in trace when a tracer is given
+func (p *roxxParser) evaluate(compiled *CompiledExpression, context context.Context, tracer *tracingParser, trace *ExpressionTrace) (result EvaluationResult) {
+	var operator string
+	context, budget := withEvaluationBudget(context)
+	budget.depth++
 	defer func() {
+		budget.depth--
 		if r := recover(); r != nil {
-			logging.GetLogger().Warn(fmt.Sprintf("Roxx Exception: Failed evaluate expression %s\n", r), nil)
+			if limitError, ok := r.(*EvaluationLimitError); ok && budget.depth > 0 {
+				// limits fail the outermost evaluation rather than the nested expression
+				if trace != nil {
+					trace.Error = limitError.Error()
+				}
+				panic(r)
+			}
+
+			err := newEvaluationError(compiled.Expression(), operator, r)
+			if budget.depth > 0 && atomic.LoadUint32(&p.strictEvaluation) == 1 {
+				// in strict mode the outermost evaluation fails with this error as its cause, it is reported there
+				if trace != nil {
+					trace.Error = err.Error()
+				}
+				panic(err)
+			}
+
+			p.logger.Warn(fmt.Sprintf("Roxx Exception: %s\n", err), nil)
+			if trace != nil {
+				trace.Error = err.Error()
+			}
+			p.handleEvaluationError(err)
+			result = NewEvaluationErrorResult(err)
 		}
 	}()
 
+	if compiled.err != nil {
+		panic(compiled.err)
+	}
+	if !compiled.isValid {
+		return NewEvaluationResult(nil)
+	}
+	if budget.depth > MaxEvaluationDepth {
+		panic(budget.limitError(EvaluationLimitDepth))
+	}
+
 	stack := NewCoreStack()
-	var result interface{}
-
-	for _, token := range tokens {
-		if token.Type == NodeTypeRand {
-			stack.Push(token.Value)
-		} else if token.Type == NodeTypeRator {
-			if handler, ok := p.operatorsMap[token.Value.(string)]; ok {
-				handler(p, stack, context)
+	var value interface{}
+
+	for i := 0; i < len(compiled.instructions); i++ {
+		instruction := compiled.instructions[i]
+		budget.operations++
+		if budget.operations > MaxEvaluationOperations {
+			panic(budget.limitError(EvaluationLimitOperations))
+		}
+		if instruction.node != nil && instruction.node.Type == NodeTypeRator {
+			operator = instruction.node.Value.(string)
+		}
+		if instruction.branch != nil {
+			var jump bool
+			if tracer != nil && instruction.node != nil {
+				jump = tracer.branch(instruction, stack, trace)
+			} else {
+				jump = instruction.branch(stack)
 			}
+			if jump {
+				i = instruction.target - 1
+			}
+		} else if instruction.operation == nil {
+			stack.Push(instruction.node.Value)
+		} else if tracer != nil {
+			tracer.apply(instruction, stack, context, trace)
 		} else {
-			return NewEvaluationResult(nil)
+			instruction.operation(p, stack, context)
 		}
 	}
 
-	result = stack.Pop()
-	if result == TokenTypeUndefined {
-		result = nil
+	operator = ""
+	value = stack.Pop()
+	if value == TokenTypeUndefined {
+		value = nil
 	}
-	return NewEvaluationResult(result)
+	return NewEvaluationResult(value)
+}
+
+func (p *roxxParser) handleEvaluationError(err *EvaluationError) {
+	holder, ok := p.errorHandler.Load().(evaluationErrorHandlerHolder)
+	if !ok || holder.handler == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("Failed to invoke the evaluation error handler", r)
+		}
+	}()
+	holder.handler(err)
+}
+
+// addShortCircuitOperator adds a built in operator that is compiled to evaluate only the operands it needs,
+// operation is applied when the expression is evaluated eagerly
+func (p *roxxParser) addShortCircuitOperator(name string, signature OperatorSignature, operation Operation) {
+	p.AddOperatorWithSignature(name, signature, operation)
+	p.shortCircuitOperators[name] = true
 }
 
 func (p *roxxParser) setBasicOperators() {
-	p.AddOperator("isUndefined", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("isUndefined", NewOperatorSignature(OperandTypeBoolean, OperandTypeAny), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		if tokenType, ok := op1.(*TokenType); !ok {
 			stack.Push(false)
@@ -83,11 +273,11 @@ func (p *roxxParser) setBasicOperators() {
 		}
 	})
 
-	p.AddOperator("now", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("now", NewOperatorSignature(OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
 		stack.Push(int(time.Now().UnixNano() / 1e6))
 	})
 
-	p.AddOperator("and", func(p Parser, stack *CoreStack, context context.Context) {
+	p.addShortCircuitOperator("and", NewOperatorSignature(OperandTypeBoolean, OperandTypeBoolean, OperandTypeBoolean), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -102,7 +292,7 @@ func (p *roxxParser) setBasicOperators() {
 		stack.Push(op1.(bool) && op2.(bool))
 	})
 
-	p.AddOperator("or", func(p Parser, stack *CoreStack, context context.Context) {
+	p.addShortCircuitOperator("or", NewOperatorSignature(OperandTypeBoolean, OperandTypeBoolean, OperandTypeBoolean), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -117,7 +307,7 @@ func (p *roxxParser) setBasicOperators() {
 		stack.Push(op1.(bool) || op2.(bool))
 	})
 
-	p.AddOperator("ne", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("ne", NewOperatorSignature(OperandTypeBoolean, OperandTypeAny, OperandTypeAny), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -132,7 +322,7 @@ func (p *roxxParser) setBasicOperators() {
 		stack.Push(op1 != op2)
 	})
 
-	p.AddOperator("eq", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("eq", NewOperatorSignature(OperandTypeBoolean, OperandTypeAny, OperandTypeAny), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -147,7 +337,7 @@ func (p *roxxParser) setBasicOperators() {
 		stack.Push(op1 == op2)
 	})
 
-	p.AddOperator("not", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("not", NewOperatorSignature(OperandTypeBoolean, OperandTypeBoolean), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 
 		if op1 == TokenTypeUndefined {
@@ -157,7 +347,7 @@ func (p *roxxParser) setBasicOperators() {
 		stack.Push(!op1.(bool))
 	})
 
-	p.AddOperator("ifThen", func(p Parser, stack *CoreStack, context context.Context) {
+	p.addShortCircuitOperator("ifThen", NewOperatorSignature(OperandTypeAny, OperandTypeBoolean, OperandTypeAny, OperandTypeAny), func(p Parser, stack *CoreStack, context context.Context) {
 		conditionExpression := stack.Pop().(bool)
 		trueExpression := stack.Pop()
 		falseExpression := stack.Pop()
@@ -169,7 +359,7 @@ func (p *roxxParser) setBasicOperators() {
 		}
 	})
 
-	p.AddOperator("inArray", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("inArray", NewOperatorSignature(OperandTypeBoolean, OperandTypeAny, OperandTypeArray), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -186,7 +376,7 @@ func (p *roxxParser) setBasicOperators() {
 		}
 	})
 
-	p.AddOperator("md5", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("md5", NewOperatorSignature(OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok := stack.Pop().(string)
 		if ok {
 			hasher := md5.New()
@@ -197,7 +387,7 @@ func (p *roxxParser) setBasicOperators() {
 		}
 	})
 
-	p.AddOperator("concat", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("concat", NewOperatorSignature(OperandTypeString, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		op2, ok2 := stack.Pop().(string)
 		if ok1 && ok2 {
@@ -207,7 +397,7 @@ func (p *roxxParser) setBasicOperators() {
 		}
 	})
 
-	p.AddOperator("b64d", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("b64d", NewOperatorSignature(OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		if ok1 {
 			sDec, _ := base64.StdEncoding.DecodeString(op1)
@@ -217,7 +407,7 @@ func (p *roxxParser) setBasicOperators() {
 		}
 	})
 
-	p.AddOperator("tsToNum", func(p Parser, stack *CoreStack, context context.Context) {
+	p.AddOperatorWithSignature("tsToNum", NewOperatorSignature(OperandTypeNumber, OperandTypeAny), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(time.Time)
 		if ok1 {
 			// for better precision using milli
@@ -1,12 +1,16 @@
 package roxx_test
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/rollout/rox-go/v6/core/context"
 	"github.com/rollout/rox-go/v6/core/extensions"
+	"github.com/rollout/rox-go/v6/core/mocks"
 	"github.com/rollout/rox-go/v6/core/properties"
 	"github.com/rollout/rox-go/v6/core/repositories"
 	"github.com/rollout/rox-go/v6/core/roxx"
@@ -192,11 +196,118 @@ func TestParserRegularExpressionEvaluation(t *testing.T) {
 	assert.Equal(t, true, parser.EvaluateExpression(`match("uS", "(IL|US)", "i")`, nil).Value())
 	assert.Equal(t, true, parser.EvaluateExpression(`match("\n", ".", "s")`, nil).Value())
 
+	// Invalid patterns and flags fail the evaluation
+	assert.Nil(t, parser.EvaluateExpression(`match("abc", "(abc", "")`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`match("abc", "abc", "q")`, nil).Value())
+	_, trace := parser.ExplainExpression(`match("abc", "(abc", "")`, nil)
+	assert.Contains(t, trace.Error, "invalid pattern (abc")
+	_, trace = parser.ExplainExpression(fmt.Sprintf(`match("abc", "%s", "")`, strings.Repeat("a", roxx.MaxRegularExpressionPatternLength+1)), nil)
+	assert.Contains(t, trace.Error, "exceeds the limit")
+
+	// Compiled patterns are reused
+	assert.Equal(t, true, parser.EvaluateExpression(`match("abc", "^a.c$", "")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`match("abd", "^a.c$", "")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`match("ABC", "^a.c$", "i")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`match("ABC", "^a.c$", "")`, nil).Value())
+
 	// Unsupported flags (x)
 	// assert.Equal(t, true, parser.EvaluateExpression(`match("uS", "IL|US#Comment", "xi")`, nil).Value())
 	// assert.Equal(t, true, parser.EvaluateExpression(`match("HELLO\nTeST\n#This is a comment", "^TEST$", "ixm")`, nil).Value())
 }
 
+func TestParserStringExpressionEvaluation(t *testing.T) {
+	parser := roxx.NewParser()
+
+	assert.Equal(t, true, parser.EvaluateExpression(`startsWith("/api/users", "/api/")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`startsWith("/API/users", "/api/")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`startsWithIgnoreCase("/API/users", "/api/")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`endsWith("test@jet.com", "@jet.com")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`endsWith("test@JET.com", "@jet.com")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`endsWithIgnoreCase("test@JET.com", "@jet.com")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`contains("hello world", "o w")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`contains("hello world", "O W")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`containsIgnoreCase("hello world", "O W")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`equalsIgnoreCase("Gold", "gOLD")`, nil).Value())
+
+	assert.Equal(t, "hello", parser.EvaluateExpression(`lower("HeLLo")`, nil).Value())
+	assert.Equal(t, "HELLO", parser.EvaluateExpression(`upper("HeLLo")`, nil).Value())
+	assert.Equal(t, "hello", parser.EvaluateExpression(`trim("  hello	")`, nil).Value())
+	assert.Equal(t, 5, parser.EvaluateExpression(`length("héllo")`, nil).Value())
+	assert.Equal(t, []interface{}{"a", "b", "c"}, parser.EvaluateExpression(`split("a,b,c", ",")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`inArray("b", split("a;b;c", ";"))`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`eq(lower(trim(" Gold ")), "gold")`, nil).Value())
+
+	// undefined propagation
+	assert.Equal(t, false, parser.EvaluateExpression(`startsWith(undefined, "a")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`containsIgnoreCase("a", 1)`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`lower(undefined)`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`length(123)`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`split(undefined, ",")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`isUndefined(upper(undefined))`, nil).Value())
+}
+
+func TestParserWillNotCompileLiteralRangesForReplacedIPOperator(t *testing.T) {
+	parser := roxx.NewParser()
+	parser.AddOperatorWithSignature("ipInCidr", roxx.NewOperatorSignature(roxx.OperandTypeAny, roxx.OperandTypeString, roxx.OperandTypeAny), func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		stack.Pop()
+		stack.Push(stack.Pop())
+	})
+
+	assert.Equal(t, []interface{}{"not a cidr"}, parser.EvaluateExpression(`ipInCidr("10.1.1.1", ["not a cidr"])`, nil).Value())
+}
+
+func TestParserArithmeticExpressionEvaluation(t *testing.T) {
+	parser := roxx.NewParser()
+
+	assert.Equal(t, 5, parser.EvaluateExpression(`add(2, 3)`, nil).Value())
+	assert.Equal(t, 5.5, parser.EvaluateExpression(`add(2.5, 3)`, nil).Value())
+	assert.Equal(t, 5, parser.EvaluateExpression(`add("2", 3)`, nil).Value())
+	assert.Equal(t, -1, parser.EvaluateExpression(`sub(2, 3)`, nil).Value())
+	assert.Equal(t, 6, parser.EvaluateExpression(`mul(2, 3)`, nil).Value())
+	assert.Equal(t, 3, parser.EvaluateExpression(`div(6, 2)`, nil).Value())
+	assert.Equal(t, 2.5, parser.EvaluateExpression(`div(5, 2)`, nil).Value())
+	assert.Equal(t, 1, parser.EvaluateExpression(`mod(7, 3)`, nil).Value())
+	assert.Equal(t, 1.5, parser.EvaluateExpression(`mod(7.5, 3)`, nil).Value())
+	assert.Equal(t, 2, parser.EvaluateExpression(`min(2, 3)`, nil).Value())
+	assert.Equal(t, 3.5, parser.EvaluateExpression(`max(2, 3.5)`, nil).Value())
+	assert.Equal(t, 4, parser.EvaluateExpression(`abs(-4)`, nil).Value())
+	assert.Equal(t, 4.5, parser.EvaluateExpression(`abs(-4.5)`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`gt(div(sub(now(), 0), 86400000), 30)`, nil).Value())
+
+	// division by zero and operands that are not numbers are undefined
+	assert.Nil(t, parser.EvaluateExpression(`div(1, 0)`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`div(1.5, 0.0)`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`mod(1, 0)`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`add(1, "a")`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`add(undefined, 1)`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`abs(true)`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`isUndefined(div(1, 0))`, nil).Value())
+}
+
+func TestParserArithmeticWillComputeOverflowingIntsWithFloats(t *testing.T) {
+	parser := roxx.NewParser()
+	maxInt := int(^uint(0) >> 1)
+	minInt := -maxInt - 1
+
+	assert.Equal(t, float64(maxInt)+1, parser.EvaluateExpression(fmt.Sprintf(`add(%d, 1)`, maxInt), nil).Value())
+	assert.Equal(t, float64(minInt)-1, parser.EvaluateExpression(fmt.Sprintf(`add(%d, -1)`, minInt), nil).Value())
+	assert.Equal(t, float64(minInt)-1, parser.EvaluateExpression(fmt.Sprintf(`sub(%d, 1)`, minInt), nil).Value())
+	assert.Equal(t, float64(maxInt)+1, parser.EvaluateExpression(fmt.Sprintf(`sub(0, %d)`, minInt), nil).Value())
+	assert.Equal(t, float64(maxInt)*2, parser.EvaluateExpression(fmt.Sprintf(`mul(%d, 2)`, maxInt), nil).Value())
+	assert.Equal(t, -float64(minInt), parser.EvaluateExpression(fmt.Sprintf(`mul(%d, -1)`, minInt), nil).Value())
+	assert.Equal(t, -float64(minInt), parser.EvaluateExpression(fmt.Sprintf(`mul(-1, %d)`, minInt), nil).Value())
+	assert.Equal(t, -float64(minInt), parser.EvaluateExpression(fmt.Sprintf(`div(%d, -1)`, minInt), nil).Value())
+	assert.Equal(t, -float64(minInt), parser.EvaluateExpression(fmt.Sprintf(`abs(%d)`, minInt), nil).Value())
+
+	// results at the limits stay ints
+	assert.Equal(t, maxInt, parser.EvaluateExpression(fmt.Sprintf(`add(%d, 1)`, maxInt-1), nil).Value())
+	assert.Equal(t, minInt, parser.EvaluateExpression(fmt.Sprintf(`sub(%d, 1)`, minInt+1), nil).Value())
+	assert.Equal(t, minInt, parser.EvaluateExpression(fmt.Sprintf(`mul(%d, 2)`, minInt/2), nil).Value())
+	assert.Equal(t, minInt, parser.EvaluateExpression(fmt.Sprintf(`add(%d, 0)`, minInt), nil).Value())
+	assert.Equal(t, maxInt, parser.EvaluateExpression(fmt.Sprintf(`abs(%d)`, minInt+1), nil).Value())
+	assert.Equal(t, maxInt, parser.EvaluateExpression(fmt.Sprintf(`div(%d, -1)`, -maxInt), nil).Value())
+}
+
 func TestParserIfThenExpressionEvaluationString(t *testing.T) {
 	parser := roxx.NewParser()
 
@@ -310,3 +421,386 @@ func TestTsToNum(t *testing.T) {
 	// non existent custom property
 	assert.Equal(t, nil, parser.EvaluateExpression(`tsToNum(property("cp3"))`, nil).Value())
 }
+
+func TestParserTimeExpressionEvaluation(t *testing.T) {
+	customPropertiesRepository := repositories.NewCustomPropertyRepository()
+	parser := roxx.NewParser()
+	extensions.NewPropertiesExtensions(parser, customPropertiesRepository, nil).Extend()
+	// a Monday, 10:30 in Berlin, 17:30 in Tokyo and 01:30 in Los Angeles
+	monday := time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC)
+	customPropertiesRepository.AddCustomProperty(properties.NewTimeProperty("t", monday))
+	customPropertiesRepository.AddCustomProperty(properties.NewTimeProperty("sunday", time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC)))
+	customPropertiesRepository.AddCustomProperty(properties.NewComputedTimeProperty("rox.now", func(ctx context.Context) time.Time {
+		return time.Now()
+	}))
+
+	assert.Equal(t, 1, parser.EvaluateExpression(`dayOfWeek(property("t"), "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, 7, parser.EvaluateExpression(`dayOfWeek(property("sunday"), "UTC")`, nil).Value())
+	assert.Equal(t, 1, parser.EvaluateExpression(`dayOfWeek(property("sunday"), "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, 10, parser.EvaluateExpression(`hourOfDay(property("t"), "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, 17, parser.EvaluateExpression(`hourOfDay(property("t"), "Asia/Tokyo")`, nil).Value())
+	assert.Equal(t, 8, parser.EvaluateExpression(fmt.Sprintf(`hourOfDay(%d, "UTC")`, monday.UnixMilli()), nil).Value())
+	assert.Equal(t, 8, parser.EvaluateExpression(`hourOfDay("2026-10-19T08:30:00Z", "UTC")`, nil).Value())
+	assert.Equal(t, 8, parser.EvaluateExpression(`hourOfDay(tsToNum(property("t")), "UTC")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`dateAfter(tsToNum(property("t")), "2026-10-19T08:30:00Z", "UTC")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`dateBefore(tsToNum(property("t")), "2026-10-19T08:30:00Z", "UTC")`, nil).Value())
+
+	assert.Equal(t, true, parser.EvaluateExpression(`dateAfter(property("t"), "2026-10-19", "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`dateAfter(property("t"), "2026-10-20", "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`dateAfter(property("t"), "2026-10-19T08:30:00Z", "Asia/Tokyo")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`dateBefore(property("t"), "2026-10-19T10:00", "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`dateBefore(property("t"), "2026-10-19T11:00", "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`dateAfter(property("rox.now"), "2000-01-01", "UTC")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`dateBefore(now(), "3000-01-01", "UTC")`, nil).Value())
+
+	assert.Equal(t, true, parser.EvaluateExpression(`inTimeWindow(property("t"), "09:00", "17:00", "Europe/Berlin")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`inTimeWindow(property("t"), "09:00", "17:00", "Asia/Tokyo")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`inTimeWindow(property("t"), "22:00", "02:00", "America/Los_Angeles")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`and(lte(dayOfWeek(property("t"), "Europe/Berlin"), 5), inTimeWindow(property("t"), "09:00", "17:00", "Europe/Berlin"))`, nil).Value())
+
+	// undefined propagation
+	assert.Nil(t, parser.EvaluateExpression(`dayOfWeek(property("t"), "Mars/Olympus")`, nil).Value())
+	assert.Nil(t, parser.EvaluateExpression(`hourOfDay(property("missing"), "UTC")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`dateAfter(property("t"), "not a date", "UTC")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`dateBefore(undefined, "2026-10-19", "UTC")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`inTimeWindow(property("t"), "9am", "17:00", "UTC")`, nil).Value())
+}
+
+func TestParserListExpressionEvaluation(t *testing.T) {
+	customPropertiesRepository := repositories.NewCustomPropertyRepository()
+	parser := roxx.NewParser()
+	extensions.NewPropertiesExtensions(parser, customPropertiesRepository, nil).Extend()
+	customPropertiesRepository.AddCustomProperty(properties.NewStringListProperty("roles", []string{"admin", "editor"}))
+	customPropertiesRepository.AddCustomProperty(properties.NewStringProperty("role", "admin"))
+
+	assert.Equal(t, true, parser.EvaluateExpression(`intersects(property("roles"), ["admin", "owner"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`intersects(property("roles"), ["viewer", "owner"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`intersects(property("roles"), [])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`containsAll(property("roles"), ["editor", "admin"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`containsAll(property("roles"), ["editor", "owner"])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`containsAll(property("roles"), [])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`isSubsetOf(property("roles"), ["admin", "editor", "viewer"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`isSubsetOf(property("roles"), ["admin"])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`isSubsetOf([], ["admin"])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`intersects(split("a,b", ","), ["b"])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`inArray("editor", property("roles"))`, nil).Value())
+
+	// operands that are not lists
+	assert.Equal(t, false, parser.EvaluateExpression(`intersects(property("role"), ["admin"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`containsAll(property("missing"), [])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`isSubsetOf(["admin"], undefined)`, nil).Value())
+}
+
+func TestParserIPExpressionEvaluation(t *testing.T) {
+	customPropertiesRepository := repositories.NewCustomPropertyRepository()
+	parser := roxx.NewParser()
+	extensions.NewPropertiesExtensions(parser, customPropertiesRepository, nil).Extend()
+	customPropertiesRepository.AddCustomProperty(properties.NewIPProperty("ip", net.ParseIP("10.20.30.40")))
+	customPropertiesRepository.AddCustomProperty(properties.NewIPProperty("ip6", net.ParseIP("2001:db8::1")))
+	customPropertiesRepository.AddCustomProperty(properties.NewStringListProperty("offices", []string{"192.168.0.0/16", "2001:db8::/32"}))
+
+	assert.Equal(t, true, parser.EvaluateExpression(`ipInCidr(property("ip"), ["10.0.0.0/8"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`ipInCidr(property("ip"), ["11.0.0.0/8", "10.20.31.0/24"])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`ipInCidr(property("ip"), ["11.0.0.0/8", "10.20.30.0/24"])`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`ipInCidr(property("ip"), "10.20.30.40")`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`ipInCidr(property("ip6"), property("offices"))`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`ipInCidr("192.168.1.1", property("offices"))`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`ipInCidr("2001:db9::1", property("offices"))`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`ipInCidr("::ffff:10.1.1.1", ["10.0.0.0/8"])`, nil).Value())
+
+	// ranges that fail to parse fail the evaluation rather than shrinking the ranges
+	assert.Equal(t, "invalid CIDR range not a cidr", parser.EvaluateExpression(`ipInCidr("10.1.1.1", ["not a cidr", "10.0.0.0/8"])`, nil).Err().Cause.Error())
+	assert.Equal(t, "invalid CIDR range 10.0.0.0/33", parser.EvaluateExpression(`ipInCidr("10.1.1.1", ["10.0.0.0/33", "10.0.0.0/8"])`, nil).Err().Cause.Error())
+	customPropertiesRepository.AddCustomProperty(properties.NewStringListProperty("invalid", []string{"10.0.0.0/8", "10.0.0.0/33"}))
+	assert.NotNil(t, parser.EvaluateExpression(`ipInCidr("10.1.1.1", property("invalid"))`, nil).Err())
+
+	// operands that are not addresses or ranges
+	assert.Equal(t, false, parser.EvaluateExpression(`ipInCidr("10.1.1", ["10.0.0.0/8"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`ipInCidr(property("missing"), ["10.0.0.0/8"])`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`ipInCidr("10.1.1.1", undefined)`, nil).Value())
+}
+
+func TestParserWillCacheCompiledExpressions(t *testing.T) {
+	parser := roxx.NewParser()
+
+	compiled := parser.CompileExpression(`eq("a", "a")`)
+
+	assert.Same(t, compiled, parser.CompileExpression(`eq("a", "a")`))
+	assert.Equal(t, `eq("a", "a")`, compiled.Expression())
+	assert.Equal(t, true, parser.EvaluateCompiledExpression(compiled, nil).Value())
+	assert.Equal(t, true, parser.EvaluateCompiledExpression(compiled, nil).Value())
+}
+
+func TestParserWillRecompileAfterOperatorIsAdded(t *testing.T) {
+	parser := roxx.NewParser()
+	assert.Nil(t, parser.EvaluateExpression(`custom("a")`, nil).Value())
+
+	parser.AddOperator("custom", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		stack.Push(stack.Pop().(string) + "b")
+	})
+
+	assert.Equal(t, "ab", parser.EvaluateExpression(`custom("a")`, nil).Value())
+}
+
+func TestParserWillNotExposeEvaluationStateToOperators(t *testing.T) {
+	parser := roxx.NewParser()
+	parser.AddOperator("onlyUserKeys", func(p roxx.Parser, stack *roxx.CoreStack, ctx context.Context) {
+		stack.Push(ctx.Get("user") == "a" && ctx.Get("rox.evaluationBudget") == nil)
+	})
+
+	result := parser.EvaluateExpression(`onlyUserKeys()`, context.NewContext(map[string]interface{}{"user": "a"}))
+
+	assert.Equal(t, true, result.Value())
+}
+
+func TestParserCanEvaluateCompiledExpressionConcurrently(t *testing.T) {
+	parser := roxx.NewParser()
+	compiled := parser.CompileExpression(`inArray("b", ["a", "b"])`)
+
+	done := make(chan bool)
+	for i := 0; i < 10; i++ {
+		go func() {
+			done <- parser.EvaluateCompiledExpression(compiled, nil).BoolValue()
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		assert.True(t, <-done)
+	}
+}
+
+func TestParserExplainWillTraceOperators(t *testing.T) {
+	parser := roxx.NewParser()
+
+	result, trace := parser.ExplainExpression(`eq("a", lt(1, 2))`, nil)
+
+	assert.Equal(t, false, result.Value())
+	assert.Equal(t, `eq("a", lt(1, 2))`, trace.Expression)
+	assert.Equal(t, false, trace.Result)
+	assert.Equal(t, 2, len(trace.Steps))
+	assert.Equal(t, "lt", trace.Steps[0].Operator)
+	assert.Equal(t, []interface{}{1, 2}, trace.Steps[0].Operands)
+	assert.Equal(t, []interface{}{true}, trace.Steps[0].Results)
+	assert.Equal(t, "eq", trace.Steps[1].Operator)
+	assert.Equal(t, []interface{}{"a", true}, trace.Steps[1].Operands)
+	assert.Equal(t, []interface{}{false}, trace.Steps[1].Results)
+	assert.Empty(t, trace.Error)
+}
+
+func TestParserExplainWillRecordFailures(t *testing.T) {
+	parser := roxx.NewParser()
+
+	result, trace := parser.ExplainExpression(`and("a", true)`, nil)
+
+	assert.Nil(t, result.Value())
+	assert.NotEmpty(t, trace.Error)
+	assert.Equal(t, "and", trace.Steps[0].Operator)
+	assert.Equal(t, []interface{}{"a"}, trace.Steps[0].Operands)
+}
+
+func TestParserWillShortCircuitAndOrIfThen(t *testing.T) {
+	customPropertiesRepository := repositories.NewCustomPropertyRepository()
+	parser := roxx.NewParser()
+	extensions.NewPropertiesExtensions(parser, customPropertiesRepository, nil).Extend()
+	calls := 0
+	customPropertiesRepository.AddCustomProperty(properties.NewComputedBooleanProperty("slow", func(ctx context.Context) bool {
+		calls++
+		return true
+	}))
+
+	assert.Equal(t, false, parser.EvaluateExpression(`and(false, property("slow"))`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`and(undefined, property("slow"))`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`or(true, property("slow"))`, nil).Value())
+	assert.Equal(t, "a", parser.EvaluateExpression(`ifThen(true, "a", property("slow"))`, nil).Value())
+	assert.Equal(t, "b", parser.EvaluateExpression(`ifThen(false, property("slow"), "b")`, nil).Value())
+	assert.Equal(t, false, parser.EvaluateExpression(`and(or(false, eq(1, 2)), ifThen(property("slow"), property("slow"), true))`, nil).Value())
+	assert.Equal(t, 0, calls)
+
+	assert.Equal(t, true, parser.EvaluateExpression(`and(true, property("slow"))`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`or(false, property("slow"))`, nil).Value())
+	assert.Equal(t, true, parser.EvaluateExpression(`ifThen(property("slow"), property("slow"), false)`, nil).Value())
+	assert.Equal(t, 4, calls)
+}
+
+func TestParserShortCircuitWillKeepResults(t *testing.T) {
+	parser := roxx.NewParser()
+
+	for expression, expected := range map[string]interface{}{
+		`and(true, true)`:                          true,
+		`and(true, false)`:                         false,
+		`and(true, undefined)`:                     false,
+		`or(false, false)`:                         false,
+		`or(false, true)`:                          true,
+		`or(undefined, undefined)`:                 false,
+		`or(false, undefined)`:                     false,
+		`ifThen(and(true, or(false, true)), 1, 2)`: 1,
+		`ifThen(eq(1, 2), 1, ifThen(true, 2, 3))`:  2,
+		`ifThen(true, undefined, 1)`:               nil,
+		`not(and(true, not(or(false, false))))`:    false,
+		`ifThen(undefined, 1, 2)`:                  nil,
+		`and(true, "a")`:                           nil,
+		`inArray(ifThen(true, "a", "b"), ["a"])`:   true,
+	} {
+		assert.Equal(t, expected, parser.EvaluateExpression(expression, nil).Value(), expression)
+	}
+}
+
+func TestParserShortCircuitWillTraceDecidingOperand(t *testing.T) {
+	parser := roxx.NewParser()
+
+	result, trace := parser.ExplainExpression(`or(eq(1, 1), lt(1, 2))`, nil)
+
+	assert.Equal(t, true, result.Value())
+	assert.Equal(t, 2, len(trace.Steps))
+	assert.Equal(t, "eq", trace.Steps[0].Operator)
+	assert.Equal(t, "or", trace.Steps[1].Operator)
+	assert.Equal(t, []interface{}{true}, trace.Steps[1].Operands)
+	assert.Equal(t, []interface{}{true}, trace.Steps[1].Results)
+}
+
+func TestParserWillEvaluateOperatorsWithoutSignatureEagerly(t *testing.T) {
+	parser := roxx.NewParser()
+	parser.AddOperator("withoutSignature", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		stack.Push(stack.Pop().(int) + 1)
+	})
+
+	assert.Equal(t, 2, parser.EvaluateExpression(`ifThen(true, withoutSignature(1), 5)`, nil).Value())
+	assert.Equal(t, 5, parser.EvaluateExpression(`ifThen(false, withoutSignature(1), 5)`, nil).Value())
+}
+
+func TestParserWillCompileLazilyOnceOperatorHasSignature(t *testing.T) {
+	logger := &mocks.Logger{}
+	logger.On("Debug", "Evaluating all operands of 'ifThen(true, 1, counted(2))', counted was added without a signature", nil).Return()
+	parser := roxx.NewParserWithLogger(logger)
+	calls := 0
+	counted := func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		calls++
+		stack.Push(stack.Pop())
+	}
+
+	parser.AddOperator("counted", counted)
+	assert.Equal(t, 1, parser.EvaluateExpression(`ifThen(true, 1, counted(2))`, nil).Value())
+	assert.Equal(t, 1, calls)
+	logger.AssertNumberOfCalls(t, "Debug", 1)
+
+	// the expression compiled eagerly is not reused
+	parser.AddOperatorWithSignature("counted", roxx.NewOperatorSignature(roxx.OperandTypeNumber, roxx.OperandTypeNumber), counted)
+	assert.Equal(t, 1, parser.EvaluateExpression(`ifThen(true, 1, counted(2))`, nil).Value())
+	assert.Equal(t, 1, calls)
+	logger.AssertNumberOfCalls(t, "Debug", 1)
+}
+
+func TestParserWillCompileExpressionsTheTokenizerCanNotRead(t *testing.T) {
+	parser := roxx.NewParser()
+
+	compiled := parser.CompileExpression(`sha256("x")`)
+	result := parser.EvaluateCompiledExpression(compiled, nil)
+
+	assert.Nil(t, result.Value())
+	assert.Contains(t, result.Err().Error(), "Excepted Number")
+}
+
+func TestParserWillReturnEvaluationError(t *testing.T) {
+	parser := roxx.NewParser()
+
+	result := parser.EvaluateExpression(`and("a", true)`, nil)
+
+	assert.Nil(t, result.Value())
+	assert.NotNil(t, result.Err())
+	assert.Equal(t, `and("a", true)`, result.Err().Expression)
+	assert.Equal(t, "and", result.Err().Operator)
+	assert.NotNil(t, result.Err().Cause)
+	assert.Contains(t, result.Err().Error(), "and")
+
+	assert.Nil(t, parser.EvaluateExpression(`and(true, true)`, nil).Err())
+	assert.Nil(t, parser.EvaluateExpression(`undefined`, nil).Err())
+}
+
+func TestParserWillInvokeEvaluationErrorHandler(t *testing.T) {
+	parser := roxx.NewParser()
+	var errs []*roxx.EvaluationError
+	parser.SetEvaluationErrorHandler(func(err *roxx.EvaluationError) {
+		errs = append(errs, err)
+	})
+
+	parser.EvaluateExpression(`eq(1, 1)`, nil)
+	parser.EvaluateExpression(`not(1)`, nil)
+
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, "not", errs[0].Operator)
+
+	parser.SetEvaluationErrorHandler(nil)
+	parser.EvaluateExpression(`not(1)`, nil)
+	assert.Equal(t, 1, len(errs))
+}
+
+func TestParserWillRecoverFromEvaluationErrorHandlerPanic(t *testing.T) {
+	parser := roxx.NewParser()
+	parser.SetEvaluationErrorHandler(func(err *roxx.EvaluationError) {
+		panic("handler failed")
+	})
+
+	result := parser.EvaluateExpression(`not("a")`, nil)
+
+	assert.Nil(t, result.Value())
+	assert.Equal(t, "not", result.Err().Operator)
+}
+
+func TestParserWillLimitEvaluationDepth(t *testing.T) {
+	parser := roxx.NewParser()
+	calls := 0
+	parser.AddOperator("deeper", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		calls++
+		stack.Push(p.EvaluateExpression(`deeper()`, context).Value())
+	})
+	var errs []*roxx.EvaluationError
+	parser.SetEvaluationErrorHandler(func(err *roxx.EvaluationError) {
+		errs = append(errs, err)
+	})
+
+	result := parser.EvaluateExpression(`deeper()`, nil)
+
+	assert.Nil(t, result.Value())
+	assert.Equal(t, "deeper", result.Err().Operator)
+	var limitError *roxx.EvaluationLimitError
+	assert.True(t, errors.As(result.Err(), &limitError))
+	assert.Equal(t, roxx.EvaluationLimitDepth, limitError.Limit)
+	assert.Equal(t, roxx.MaxEvaluationDepth, calls)
+	assert.Equal(t, 1, len(errs))
+
+	// the budget is per evaluation
+	calls = 0
+	parser.EvaluateExpression(`deeper()`, nil)
+	assert.Equal(t, roxx.MaxEvaluationDepth, calls)
+}
+
+func TestParserWillLimitEvaluationOperations(t *testing.T) {
+	parser := roxx.NewParser()
+	parser.AddOperator("grow", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		n := stack.Pop().(int)
+		if n == 0 {
+			stack.Push(false)
+			return
+		}
+		stack.Push(p.EvaluateExpression(fmt.Sprintf(`or(grow(%d), grow(%d))`, n-1, n-1), context).BoolValue())
+	})
+
+	assert.Equal(t, false, parser.EvaluateExpression(`grow(4)`, nil).Value())
+
+	result := parser.EvaluateExpression(`grow(20)`, nil)
+
+	assert.Nil(t, result.Value())
+	var limitError *roxx.EvaluationLimitError
+	assert.True(t, errors.As(result.Err(), &limitError))
+	assert.Equal(t, roxx.EvaluationLimitOperations, limitError.Limit)
+}
+
+func TestParserWillRecompileStaleCompiledExpression(t *testing.T) {
+	parser := roxx.NewParser()
+	compiled := parser.CompileExpression(`later()`)
+
+	parser.AddOperator("later", func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		stack.Push(1)
+	})
+
+	assert.Equal(t, 1, parser.EvaluateCompiledExpression(compiled, nil).Value())
+}
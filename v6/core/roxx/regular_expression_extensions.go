@@ -5,18 +5,30 @@ import (
 	"regexp"
 
 	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/utils"
 )
 
+// DefaultRegularExpressionCacheSize is the number of compiled patterns a RegularExpressionExtensions keeps
+const DefaultRegularExpressionCacheSize = 1000
+
+// MaxRegularExpressionPatternLength is the longest pattern match compiles, longer patterns fail the evaluation
+const MaxRegularExpressionPatternLength = 4096
+
 type RegularExpressionExtensions struct {
 	parser Parser
+	// regexpCache holds a *regexp.Regexp or the compilation error by flags and pattern
+	regexpCache *utils.LRUCache
 }
 
 func NewRegularExpressionExtensions(parser Parser) *RegularExpressionExtensions {
-	return &RegularExpressionExtensions{parser: parser}
+	return &RegularExpressionExtensions{
+		parser:      parser,
+		regexpCache: utils.NewLRUCache(DefaultRegularExpressionCacheSize),
+	}
 }
 
 func (e *RegularExpressionExtensions) Extend() {
-	e.parser.AddOperator("match", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("match", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		str, ok1 := stack.Pop().(string)
 		pattern, ok2 := stack.Pop().(string)
 		flags, ok3 := stack.Pop().(string)
@@ -26,11 +38,45 @@ func (e *RegularExpressionExtensions) Extend() {
 			return
 		}
 
-		if flags != "" {
-			pattern = fmt.Sprintf("(?%s)%s", flags, pattern)
+		compiled, err := e.compile(pattern, flags)
+		if err != nil {
+			// fails the evaluation, the expression is undefined and the error is logged
+			panic(err)
 		}
-
-		matched, _ := regexp.MatchString(pattern, str)
-		stack.Push(matched)
+		stack.Push(compiled.MatchString(str))
 	})
 }
+
+func (e *RegularExpressionExtensions) compile(pattern, flags string) (*regexp.Regexp, error) {
+	key := flags + "/" + pattern
+	if cached, ok := e.regexpCache.Get(key); ok {
+		if err, ok := cached.(error); ok {
+			return nil, err
+		}
+		return cached.(*regexp.Regexp), nil
+	}
+
+	compiled, err := compileRegularExpression(pattern, flags)
+	if err != nil {
+		e.regexpCache.Add(key, err)
+		return nil, err
+	}
+	e.regexpCache.Add(key, compiled)
+	return compiled, nil
+}
+
+func compileRegularExpression(pattern, flags string) (*regexp.Regexp, error) {
+	if len(pattern) > MaxRegularExpressionPatternLength {
+		return nil, fmt.Errorf("pattern of %d bytes exceeds the limit of %d", len(pattern), MaxRegularExpressionPatternLength)
+	}
+
+	fullPattern := pattern
+	if flags != "" {
+		fullPattern = fmt.Sprintf("(?%s)%s", flags, pattern)
+	}
+	compiled, err := regexp.Compile(fullPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %v", fullPattern, err)
+	}
+	return compiled, nil
+}
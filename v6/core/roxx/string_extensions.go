@@ -0,0 +1,89 @@
+package roxx
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rollout/rox-go/v6/core/context"
+)
+
+type StringExtensions struct {
+	parser Parser
+}
+
+func NewStringExtensions(parser Parser) *StringExtensions {
+	return &StringExtensions{parser: parser}
+}
+
+func (e *StringExtensions) Extend() {
+	e.addPredicate("startsWith", strings.HasPrefix)
+	e.addPredicate("endsWith", strings.HasSuffix)
+	e.addPredicate("contains", strings.Contains)
+	e.addPredicate("startsWithIgnoreCase", func(str, prefix string) bool {
+		return strings.HasPrefix(strings.ToLower(str), strings.ToLower(prefix))
+	})
+	e.addPredicate("endsWithIgnoreCase", func(str, suffix string) bool {
+		return strings.HasSuffix(strings.ToLower(str), strings.ToLower(suffix))
+	})
+	e.addPredicate("containsIgnoreCase", func(str, substr string) bool {
+		return strings.Contains(strings.ToLower(str), strings.ToLower(substr))
+	})
+	e.addPredicate("equalsIgnoreCase", strings.EqualFold)
+
+	e.addTransform("lower", OperandTypeString, func(str string) interface{} {
+		return strings.ToLower(str)
+	})
+	e.addTransform("upper", OperandTypeString, func(str string) interface{} {
+		return strings.ToUpper(str)
+	})
+	e.addTransform("trim", OperandTypeString, func(str string) interface{} {
+		return strings.TrimSpace(str)
+	})
+	e.addTransform("length", OperandTypeNumber, func(str string) interface{} {
+		return utf8.RuneCountInString(str)
+	})
+
+	e.parser.AddOperatorWithSignature("split", NewOperatorSignature(OperandTypeArray, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		str, ok1 := stack.Pop().(string)
+		separator, ok2 := stack.Pop().(string)
+
+		if !ok1 || !ok2 {
+			stack.Push(TokenTypeUndefined)
+			return
+		}
+
+		parts := strings.Split(str, separator)
+		items := make([]interface{}, len(parts))
+		for i, part := range parts {
+			items[i] = part
+		}
+		stack.Push(items)
+	})
+}
+
+// addPredicate adds an operator testing two strings, like match it is false when an operand is not a string
+func (e *StringExtensions) addPredicate(name string, predicate func(str, other string) bool) {
+	e.parser.AddOperatorWithSignature(name, NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		str, ok1 := stack.Pop().(string)
+		other, ok2 := stack.Pop().(string)
+
+		if !ok1 || !ok2 {
+			stack.Push(false)
+		} else {
+			stack.Push(predicate(str, other))
+		}
+	})
+}
+
+// addTransform adds an operator computing a value from a string, like md5 it is undefined when the operand is not a string
+func (e *StringExtensions) addTransform(name string, result OperandType, transform func(str string) interface{}) {
+	e.parser.AddOperatorWithSignature(name, NewOperatorSignature(result, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		str, ok := stack.Pop().(string)
+
+		if !ok {
+			stack.Push(TokenTypeUndefined)
+		} else {
+			stack.Push(transform(str))
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package roxx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rollout/rox-go/v6/core/context"
+)
+
+// layouts of the dates read from string operands, a date without an offset is read in the operator's timezone
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+const timeOfDayLayout = "15:04"
+
+// TimeExtensions adds calendar operators, times are time.Time values such as rox.now and time properties,
+// ints of milliseconds since the epoch as pushed by now, floats of seconds since the epoch as pushed by tsToNum,
+// or date strings. Every operator takes an IANA timezone
+type TimeExtensions struct {
+	parser    Parser
+	locations sync.Map
+}
+
+func NewTimeExtensions(parser Parser) *TimeExtensions {
+	return &TimeExtensions{parser: parser}
+}
+
+func (e *TimeExtensions) Extend() {
+	// dayOfWeek is 1 for Monday through 7 for Sunday
+	e.parser.AddOperatorWithSignature("dayOfWeek", NewOperatorSignature(OperandTypeNumber, OperandTypeAny, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		t, ok := e.popTimeInLocation(stack)
+		if !ok {
+			stack.Push(TokenTypeUndefined)
+			return
+		}
+
+		weekday := int(t.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		stack.Push(weekday)
+	})
+
+	e.parser.AddOperatorWithSignature("hourOfDay", NewOperatorSignature(OperandTypeNumber, OperandTypeAny, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		t, ok := e.popTimeInLocation(stack)
+		if !ok {
+			stack.Push(TokenTypeUndefined)
+			return
+		}
+
+		stack.Push(t.Hour())
+	})
+
+	e.parser.AddOperatorWithSignature("dateBefore", NewOperatorSignature(OperandTypeBoolean, OperandTypeAny, OperandTypeAny, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		t, date, ok := e.popTimeAndDate(stack)
+		stack.Push(ok && t.Before(date))
+	})
+
+	e.parser.AddOperatorWithSignature("dateAfter", NewOperatorSignature(OperandTypeBoolean, OperandTypeAny, OperandTypeAny, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		t, date, ok := e.popTimeAndDate(stack)
+		stack.Push(ok && !t.Before(date))
+	})
+
+	// inTimeWindow is true from start until before end, a window ending before it starts spans midnight
+	e.parser.AddOperatorWithSignature("inTimeWindow", NewOperatorSignature(OperandTypeBoolean, OperandTypeAny, OperandTypeString, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
+		value := stack.Pop()
+		start, ok1 := e.toTimeOfDay(stack.Pop())
+		end, ok2 := e.toTimeOfDay(stack.Pop())
+		location, ok3 := e.location(stack.Pop())
+		t, ok4 := e.toTime(value, location)
+
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			stack.Push(false)
+			return
+		}
+
+		t = t.In(location)
+		minutes := t.Hour()*60 + t.Minute()
+		if start <= end {
+			stack.Push(minutes >= start && minutes < end)
+		} else {
+			stack.Push(minutes >= start || minutes < end)
+		}
+	})
+}
+
+func (e *TimeExtensions) popTimeInLocation(stack *CoreStack) (time.Time, bool) {
+	value := stack.Pop()
+	location, ok := e.location(stack.Pop())
+	if !ok {
+		return time.Time{}, false
+	}
+
+	t, ok := e.toTime(value, location)
+	return t.In(location), ok
+}
+
+func (e *TimeExtensions) popTimeAndDate(stack *CoreStack) (time.Time, time.Time, bool) {
+	value := stack.Pop()
+	dateValue := stack.Pop()
+	location, ok := e.location(stack.Pop())
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	t, ok1 := e.toTime(value, location)
+	date, ok2 := e.toTime(dateValue, location)
+	return t, date, ok1 && ok2
+}
+
+func (e *TimeExtensions) location(value interface{}) (*time.Location, bool) {
+	name, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	if location, ok := e.locations.Load(name); ok {
+		return location.(*time.Location), true
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	e.locations.Store(name, location)
+	return location, true
+}
+
+func (e *TimeExtensions) toTime(value interface{}, location *time.Location) (time.Time, bool) {
+	switch value := value.(type) {
+	case time.Time:
+		return value, true
+	case int:
+		return time.Unix(0, int64(value)*int64(time.Millisecond)), true
+	case float64:
+		return time.Unix(0, int64(value*float64(time.Second))), true
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, value, location); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// toTimeOfDay reads HH:MM as minutes since midnight
+func (e *TimeExtensions) toTimeOfDay(value interface{}) (int, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+
+	t, err := time.Parse(timeOfDayLayout, str)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
@@ -1,6 +1,7 @@
 package roxx
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -38,6 +39,14 @@ func newTokenType(text, pattern string) *TokenType {
 	}
 }
 
+func (tt *TokenType) String() string {
+	return tt.text
+}
+
+func (tt *TokenType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tt.text)
+}
+
 func (tt *TokenType) IsNumber() bool {
 	return tt == TokenTypeNumber
 }
@@ -25,6 +25,8 @@ type TokenizedExpression struct {
 	arrayAccumulator []interface{}
 	dictAccumulator  map[string]interface{}
 	dictKey          string
+	// problems are the structural errors found by the last tokenize, they are reported by Validate
+	problems []string
 }
 
 func NewTokenizedExpression(expression string, operators []string) *TokenizedExpression {
@@ -70,6 +72,7 @@ func (te *TokenizedExpression) tokenize(expression string) []*Node {
 	te.dictAccumulator = nil
 	te.arrayAccumulator = nil
 	te.dictKey = ""
+	te.problems = nil
 
 	delimitersToUse := tokenDelimiters
 	normalizedExpression := te.normalize(expression)
@@ -82,14 +85,26 @@ func (te *TokenizedExpression) tokenize(expression string) []*Node {
 		inString := delimitersToUse == stringDelimiter
 
 		if !inString && token == dictStartDelimiter {
+			if te.dictAccumulator != nil {
+				te.problems = append(te.problems, "nested dictionaries are not supported")
+			}
 			te.dictAccumulator = make(map[string]interface{})
 		} else if !inString && token == dictEndDelimiter {
+			if te.dictAccumulator == nil {
+				te.problems = append(te.problems, "unbalanced '}'")
+			}
 			dictResult := te.dictAccumulator
 			te.dictAccumulator = nil
 			te.pushNode(te.nodeFromDict(dictResult))
 		} else if !inString && token == arrayStartDelimiter {
+			if te.arrayAccumulator != nil {
+				te.problems = append(te.problems, "nested arrays are not supported")
+			}
 			te.arrayAccumulator = make([]interface{}, 0)
 		} else if !inString && token == arrayEndDelimiter {
+			if te.arrayAccumulator == nil {
+				te.problems = append(te.problems, "unbalanced ']'")
+			}
 			arrayResult := te.arrayAccumulator
 			te.arrayAccumulator = nil
 			te.pushNode(te.nodeFromArray(arrayResult))
@@ -112,6 +127,16 @@ func (te *TokenizedExpression) tokenize(expression string) []*Node {
 		}
 	}
 
+	if delimitersToUse == stringDelimiter {
+		te.problems = append(te.problems, "unterminated string")
+	}
+	if te.arrayAccumulator != nil {
+		te.problems = append(te.problems, "unbalanced '['")
+	}
+	if te.dictAccumulator != nil {
+		te.problems = append(te.problems, "unbalanced '{'")
+	}
+
 	return te.resultList
 }
 
@@ -162,7 +187,7 @@ func (te *TokenizedExpression) nodeFromToken(token string) *Node {
 		panic(fmt.Sprintf("Excepted Number, got '%s' (%s)", token, tokenType.text))
 	}
 
-	return NewNode(NodeTypeUnknown, nil)
+	return NewNode(NodeTypeUnknown, token)
 }
 
 func (te *TokenizedExpression) isOperator(token string) bool {
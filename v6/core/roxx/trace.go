@@ -0,0 +1,111 @@
+package roxx
+
+import (
+	"github.com/rollout/rox-go/v6/core/context"
+)
+
+// ExpressionTrace describes how an expression was evaluated
+type ExpressionTrace struct {
+	Expression string           `json:"expression"`
+	Steps      []*OperatorTrace `json:"steps"`
+	Result     interface{}      `json:"result"`
+	// Error is set when the evaluation failed, the result is then undefined
+	Error string `json:"error,omitempty"`
+}
+
+// OperatorTrace describes a single operation, operands are listed in argument order.
+// and, or and ifThen record a step for the operand deciding which operands are evaluated next
+type OperatorTrace struct {
+	Operator string        `json:"operator"`
+	Operands []interface{} `json:"operands"`
+	Results  []interface{} `json:"results"`
+	// Details holds values the operation computed that are not visible on the stack, such as percentage buckets
+	Details map[string]interface{} `json:"details,omitempty"`
+	// SubExpressions are the expressions the operation evaluated, such as target group conditions
+	SubExpressions []*ExpressionTrace `json:"subExpressions,omitempty"`
+}
+
+// Tracer is implemented by the parser handed to operations while an expression is explained
+type Tracer interface {
+	AddTraceDetail(key string, value interface{})
+}
+
+// AddTraceDetail records a detail of the running operation when p is explaining an expression
+func AddTraceDetail(p Parser, key string, value interface{}) {
+	if tracer, ok := p.(Tracer); ok {
+		tracer.AddTraceDetail(key, value)
+	}
+}
+
+// tracingParser is passed to operations instead of the parser so that the expressions they evaluate are traced too
+type tracingParser struct {
+	*roxxParser
+	step *OperatorTrace
+}
+
+func newTracingParser(p *roxxParser) *tracingParser {
+	return &tracingParser{roxxParser: p}
+}
+
+func (tp *tracingParser) explain(expression string, context context.Context) (EvaluationResult, *ExpressionTrace) {
+	trace := &ExpressionTrace{Expression: expression, Steps: []*OperatorTrace{}}
+	result := tp.evaluate(tp.CompileExpression(expression), context, tp, trace)
+	trace.Result = result.Value()
+	return result, trace
+}
+
+func (tp *tracingParser) EvaluateExpression(expression string, context context.Context) EvaluationResult {
+	step := tp.step
+	result, trace := tp.explain(expression, context)
+	if step != nil {
+		step.SubExpressions = append(step.SubExpressions, trace)
+	}
+	return result
+}
+
+func (tp *tracingParser) EvaluateCompiledExpression(compiled *CompiledExpression, context context.Context) EvaluationResult {
+	return tp.EvaluateExpression(compiled.Expression(), context)
+}
+
+func (tp *tracingParser) AddTraceDetail(key string, value interface{}) {
+	if tp.step == nil {
+		return
+	}
+	if tp.step.Details == nil {
+		tp.step.Details = make(map[string]interface{})
+	}
+	tp.step.Details[key] = value
+}
+
+func (tp *tracingParser) apply(instruction instruction, stack *CoreStack, context context.Context, trace *ExpressionTrace) {
+	tp.record(instruction, stack, trace, func() {
+		instruction.operation(tp, stack, context)
+	})
+}
+
+// branch records the operand a short-circuit instruction popped, and the value it pushed when it decided the result
+func (tp *tracingParser) branch(instruction instruction, stack *CoreStack, trace *ExpressionTrace) (jump bool) {
+	tp.record(instruction, stack, trace, func() {
+		jump = instruction.branch(stack)
+	})
+	return jump
+}
+
+func (tp *tracingParser) record(instruction instruction, stack *CoreStack, trace *ExpressionTrace, run func()) {
+	step := &OperatorTrace{Operator: instruction.node.Value.(string)}
+	trace.Steps = append(trace.Steps, step)
+
+	before := append([]interface{}(nil), stack.items...)
+	stack.lowWaterMark = len(stack.items)
+	previousStep := tp.step
+	tp.step = step
+	defer func() {
+		tp.step = previousStep
+		for i := len(before) - 1; i >= stack.lowWaterMark; i-- {
+			step.Operands = append(step.Operands, before[i])
+		}
+		step.Results = append([]interface{}(nil), stack.items[stack.lowWaterMark:]...)
+	}()
+
+	run()
+}
@@ -0,0 +1,221 @@
+package roxx
+
+import (
+	"fmt"
+
+	"github.com/rollout/rox-go/v6/core/utils"
+)
+
+// OperandType is the type of an operand as far as it is known without evaluating the expression
+type OperandType int
+
+const (
+	OperandTypeAny OperandType = iota
+	OperandTypeString
+	OperandTypeNumber
+	OperandTypeBoolean
+	OperandTypeArray
+	OperandTypeDict
+	OperandTypeUndefined
+)
+
+func (ot OperandType) String() string {
+	switch ot {
+	case OperandTypeString:
+		return "string"
+	case OperandTypeNumber:
+		return "number"
+	case OperandTypeBoolean:
+		return "boolean"
+	case OperandTypeArray:
+		return "array"
+	case OperandTypeDict:
+		return "dictionary"
+	case OperandTypeUndefined:
+		return "undefined"
+	}
+	return "any"
+}
+
+// OperatorSignature describes the operands an operator pops, in argument order, and the value it pushes
+type OperatorSignature struct {
+	Operands []OperandType
+	Result   OperandType
+}
+
+// NewOperatorSignature returns the signature of an operator popping operands, in argument order, and pushing result
+func NewOperatorSignature(result OperandType, operands ...OperandType) OperatorSignature {
+	return OperatorSignature{Operands: operands, Result: result}
+}
+
+// literalChecks validate literal operands beyond their type, they return a message for invalid operands
+var literalChecks = map[string]func(operands []staticOperand) string{
+	"match": func(operands []staticOperand) string {
+		pattern, flags := operands[1], operands[2]
+		if !pattern.isLiteral || !flags.isLiteral || pattern.operandType != OperandTypeString || flags.operandType != OperandTypeString {
+			return ""
+		}
+		if _, err := compileRegularExpression(pattern.value.(string), flags.value.(string)); err != nil {
+			return err.Error()
+		}
+		return ""
+	},
+	"ipInCidr": func(operands []staticOperand) string {
+		ranges := operands[1]
+		if !ranges.isLiteral {
+			return ""
+		}
+		if networks := parseNetworks(ranges.value); networks != nil && networks.err != nil {
+			return networks.err.Error()
+		}
+		return ""
+	},
+}
+
+// ValidationError is a problem found in an expression without evaluating it
+type ValidationError struct {
+	Expression string
+	// Operator is the operator the problem was found at, it is empty for problems with the expression structure
+	Operator string
+	Message  string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Operator == "" {
+		return fmt.Sprintf("%s in '%s'", e.Message, e.Expression)
+	}
+	return fmt.Sprintf("%s: %s in '%s'", e.Operator, e.Message, e.Expression)
+}
+
+// ExpressionAnalysis is the result of checking an expression without a context
+type ExpressionAnalysis struct {
+	Errors []*ValidationError
+	// References holds the string literals operators are called with as their first operand, keyed by operator,
+	// such as the target groups checked with isInTargetGroup
+	References map[string][]string
+}
+
+// Validate checks expression against the operators of parser without a context,
+// it returns no errors when the expression is well formed
+func Validate(parser Parser, expression string) []*ValidationError {
+	return Analyze(parser, expression).Errors
+}
+
+// Analyze checks expression against the operators of parser without a context and collects the literals it refers to
+func Analyze(parser Parser, expression string) (analysis *ExpressionAnalysis) {
+	analysis = &ExpressionAnalysis{References: make(map[string][]string)}
+	defer func() {
+		if r := recover(); r != nil {
+			analysis.addError(expression, "", fmt.Sprintf("%v", r))
+		}
+	}()
+
+	tokenizedExpression := NewTokenizedExpression(expression, parser.Operators())
+	tokens := tokenizedExpression.GetTokens()
+	for _, problem := range tokenizedExpression.problems {
+		analysis.addError(expression, "", problem)
+	}
+
+	// the stack holds what the operands are known to be, literals keep their value
+	var stack []staticOperand
+	arityKnown := true
+	for i := len(tokens) - 1; i >= 0; i-- {
+		token := tokens[i]
+		switch token.Type {
+		case NodeTypeRand:
+			stack = append(stack, literalOperand(token.Value))
+		case NodeTypeRator:
+			operator := token.Value.(string)
+			signature, ok := parser.OperatorSignature(operator)
+			if !ok {
+				// an operator added without a signature is known, but its operands can not be told apart from the others
+				arityKnown = false
+				stack = append(stack, staticOperand{operandType: OperandTypeAny})
+				continue
+			}
+			if len(stack) < len(signature.Operands) {
+				if arityKnown {
+					analysis.addError(expression, operator, fmt.Sprintf("expects %d operands, got %d", len(signature.Operands), len(stack)))
+				}
+				stack = append(stack[:0], staticOperand{operandType: signature.Result})
+				continue
+			}
+
+			for j, expected := range signature.Operands {
+				operand := stack[len(stack)-1-j]
+				if j == 0 && operand.operandType == OperandTypeString && operand.isLiteral {
+					analysis.References[operator] = append(analysis.References[operator], operand.value.(string))
+				}
+				if arityKnown && !operand.accepts(expected) {
+					analysis.addError(expression, operator, fmt.Sprintf("operand %d expects %s, got %s %v", j+1, expected, operand.operandType, operand.value))
+				}
+			}
+			if check, ok := literalChecks[operator]; ok && arityKnown {
+				operands := make([]staticOperand, len(signature.Operands))
+				for j := range operands {
+					operands[j] = stack[len(stack)-1-j]
+				}
+				if message := check(operands); message != "" {
+					analysis.addError(expression, operator, message)
+				}
+			}
+			stack = append(stack[:len(stack)-len(signature.Operands)], staticOperand{operandType: signature.Result})
+		default:
+			analysis.addError(expression, fmt.Sprintf("%v", token.Value), "unknown operator")
+			// the operands of an unknown operator can not be told apart from the ones it leaves for the next operator,
+			// so operands are not checked past it
+			arityKnown = false
+		}
+	}
+
+	if len(tokens) == 0 && len(tokenizedExpression.problems) == 0 {
+		analysis.addError(expression, "", "expression is empty")
+	} else if arityKnown && len(stack) > 1 {
+		analysis.addError(expression, "", fmt.Sprintf("expression leaves %d values, expected 1", len(stack)))
+	}
+	return analysis
+}
+
+func (a *ExpressionAnalysis) addError(expression, operator, message string) {
+	a.Errors = append(a.Errors, &ValidationError{Expression: expression, Operator: operator, Message: message})
+}
+
+type staticOperand struct {
+	operandType OperandType
+	value       interface{}
+	isLiteral   bool
+}
+
+func literalOperand(value interface{}) staticOperand {
+	operand := staticOperand{value: value, isLiteral: true}
+	switch value.(type) {
+	case string:
+		operand.operandType = OperandTypeString
+	case int, float64:
+		operand.operandType = OperandTypeNumber
+	case bool:
+		operand.operandType = OperandTypeBoolean
+	case []interface{}:
+		operand.operandType = OperandTypeArray
+	case map[string]interface{}:
+		operand.operandType = OperandTypeDict
+	case *TokenType:
+		operand.operandType = OperandTypeUndefined
+	}
+	return operand
+}
+
+func (so staticOperand) accepts(expected OperandType) bool {
+	if expected == OperandTypeAny || so.operandType == OperandTypeAny || so.operandType == OperandTypeUndefined {
+		return true
+	}
+	if expected == OperandTypeNumber && so.operandType == OperandTypeString {
+		// numbers are also read from strings, only literals can be told apart
+		if !so.isLiteral {
+			return true
+		}
+		_, ok := utils.ToFloat(so.value)
+		return ok
+	}
+	return so.operandType == expected
+}
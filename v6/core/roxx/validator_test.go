@@ -0,0 +1,164 @@
+package roxx_test
+
+import (
+	"testing"
+
+	"github.com/rollout/rox-go/v6/core/context"
+	"github.com/rollout/rox-go/v6/core/extensions"
+	"github.com/rollout/rox-go/v6/core/roxx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWillAcceptValidExpressions(t *testing.T) {
+	parser := newExpressionParser()
+
+	for _, expression := range []string{
+		`true`,
+		`and(true, or(false, not(false)))`,
+		`ifThen(eq("a", "b"), 1, undefined)`,
+		`inArray("a", ["a", "b"])`,
+		`lt("1.5", 2)`,
+		`match("abc", "a.c", "i")`,
+		`isInPercentageRange(0, 0.5, mergeSeed("id", property("user")))`,
+		`semverGte(property("version"), "1.2.3")`,
+	} {
+		assert.Empty(t, roxx.Validate(parser, expression), expression)
+	}
+}
+
+func TestValidateWillReportUnknownOperators(t *testing.T) {
+	parser := newExpressionParser()
+
+	errors := roxx.Validate(parser, `and(true, isSomething("x"))`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "isSomething", errors[0].Operator)
+	assert.Equal(t, "unknown operator", errors[0].Message)
+}
+
+func TestValidateWillReportArityMismatch(t *testing.T) {
+	parser := newExpressionParser()
+
+	errors := roxx.Validate(parser, `ifThen(and(true, true)`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "ifThen", errors[0].Operator)
+	assert.Equal(t, "expects 3 operands, got 1", errors[0].Message)
+
+	errors = roxx.Validate(parser, `not(true, true)`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "", errors[0].Operator)
+	assert.Equal(t, "expression leaves 2 values, expected 1", errors[0].Message)
+}
+
+func TestValidateWillReportWrongLiteralTypes(t *testing.T) {
+	parser := newExpressionParser()
+
+	errors := roxx.Validate(parser, `and("yes", true)`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "and", errors[0].Operator)
+	assert.Equal(t, "operand 1 expects boolean, got string yes", errors[0].Message)
+
+	errors = roxx.Validate(parser, `gt("abc", 1)`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "operand 1 expects number, got string abc", errors[0].Message)
+
+	errors = roxx.Validate(parser, `inArray("a", "b")`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "operand 2 expects array, got string b", errors[0].Message)
+}
+
+func TestValidateWillReportInvalidPatterns(t *testing.T) {
+	parser := newExpressionParser()
+
+	errors := roxx.Validate(parser, `match(property("email"), "(.*@jet\.com", "")`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "match", errors[0].Operator)
+	assert.Contains(t, errors[0].Message, "invalid pattern")
+
+	assert.Empty(t, roxx.Validate(parser, `match(property("email"), property("pattern"), "")`))
+}
+
+func TestValidateWillReportInvalidCidrRanges(t *testing.T) {
+	parser := newExpressionParser()
+
+	errors := roxx.Validate(parser, `ipInCidr(property("ip"), ["10.0.0.0/8", "10.0.0.0/33"])`)
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "ipInCidr", errors[0].Operator)
+	assert.Equal(t, "invalid CIDR range 10.0.0.0/33", errors[0].Message)
+
+	assert.Empty(t, roxx.Validate(parser, `ipInCidr(property("ip"), ["10.0.0.0/8", "2001:db8::/32", "10.1.1.1"])`))
+	assert.Empty(t, roxx.Validate(parser, `ipInCidr(property("ip"), property("ranges"))`))
+}
+
+func TestValidateWillReportUnbalancedArraysAndDicts(t *testing.T) {
+	parser := newExpressionParser()
+
+	for expression, message := range map[string]string{
+		`inArray("a", ["a", "b")`:    "unbalanced '['",
+		`inArray("a", "a", "b"])`:    "unbalanced ']'",
+		`eq({"a": 1, "b"}, {"a"`:     "unbalanced '{'",
+		`eq("a", "b"})`:              "unbalanced '}'",
+		`eq("a", "b)`:                "unterminated string",
+		`inArray("a", [["a"], "b"])`: "nested arrays are not supported",
+	} {
+		errors := roxx.Validate(parser, expression)
+
+		assert.NotEmpty(t, errors, expression)
+		assert.Equal(t, message, errors[0].Message, expression)
+	}
+}
+
+func TestValidateWillReportEmptyExpression(t *testing.T) {
+	parser := newExpressionParser()
+
+	errors := roxx.Validate(parser, "")
+
+	assert.Equal(t, 1, len(errors))
+	assert.Equal(t, "expression is empty", errors[0].Message)
+}
+
+func TestAnalyzeWillCollectReferences(t *testing.T) {
+	parser := newExpressionParser()
+
+	analysis := roxx.Analyze(parser, `and(isInTargetGroup("tg1"), eq(flagValue("flag1"), property("prop")))`)
+
+	assert.Empty(t, analysis.Errors)
+	assert.Equal(t, []string{"tg1"}, analysis.References["isInTargetGroup"])
+	assert.Equal(t, []string{"flag1"}, analysis.References["flagValue"])
+	assert.Equal(t, []string{"prop"}, analysis.References["property"])
+}
+
+func TestValidateWillUseSignaturesOfTheParser(t *testing.T) {
+	parser := roxx.NewParser()
+	operation := func(p roxx.Parser, stack *roxx.CoreStack, context context.Context) {
+		stack.Push(stack.Pop() == "a")
+	}
+
+	assert.Equal(t, "unknown operator", roxx.Validate(parser, `customOperator("a")`)[0].Message)
+
+	// an operator added without a signature is known, its operands are not checked
+	parser.AddOperator("customOperator", operation)
+	assert.Empty(t, roxx.Validate(parser, `customOperator(1)`))
+
+	parser.AddOperatorWithSignature("customOperator", roxx.NewOperatorSignature(roxx.OperandTypeBoolean, roxx.OperandTypeString), operation)
+	assert.Empty(t, roxx.Validate(parser, `customOperator("a")`))
+	assert.Equal(t, "operand 1 expects string, got number 1", roxx.Validate(parser, `customOperator(1)`)[0].Message)
+
+	// signatures belong to the parser they were added to
+	assert.Equal(t, "unknown operator", roxx.Validate(roxx.NewParser(), `customOperator("a")`)[0].Message)
+}
+
+// newExpressionParser returns a parser with the operators configurations are evaluated with
+func newExpressionParser() roxx.Parser {
+	parser := roxx.NewParser()
+	extensions.NewExperimentsExtensions(parser, nil, nil, nil).Extend()
+	extensions.NewPropertiesExtensions(parser, nil, nil).Extend()
+	return parser
+}
@@ -17,7 +17,7 @@ func NewValueCompareExtensions(parser Parser) *ValueCompareExtensions {
 }
 
 func (e *ValueCompareExtensions) Extend() {
-	e.parser.AddOperator("lt", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("lt", NewOperatorSignature(OperandTypeBoolean, OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -31,7 +31,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("lte", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("lte", NewOperatorSignature(OperandTypeBoolean, OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -45,7 +45,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("gt", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("gt", NewOperatorSignature(OperandTypeBoolean, OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -59,7 +59,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("gte", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("gte", NewOperatorSignature(OperandTypeBoolean, OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -73,7 +73,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("numeq", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("numeq", NewOperatorSignature(OperandTypeBoolean, OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -87,7 +87,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("numneq", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("numneq", NewOperatorSignature(OperandTypeBoolean, OperandTypeNumber, OperandTypeNumber), func(p Parser, stack *CoreStack, context context.Context) {
 		op1 := stack.Pop()
 		op2 := stack.Pop()
 
@@ -101,7 +101,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("semverNe", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("semverNe", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		op2, ok2 := stack.Pop().(string)
 
@@ -119,7 +119,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("semverEq", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("semverEq", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		op2, ok2 := stack.Pop().(string)
 
@@ -137,7 +137,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("semverLt", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("semverLt", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		op2, ok2 := stack.Pop().(string)
 
@@ -155,7 +155,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("semverLte", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("semverLte", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		op2, ok2 := stack.Pop().(string)
 
@@ -173,7 +173,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("semverGt", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("semverGt", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		op2, ok2 := stack.Pop().(string)
 
@@ -191,7 +191,7 @@ func (e *ValueCompareExtensions) Extend() {
 		}
 	})
 
-	e.parser.AddOperator("semverGte", func(p Parser, stack *CoreStack, context context.Context) {
+	e.parser.AddOperatorWithSignature("semverGte", NewOperatorSignature(OperandTypeBoolean, OperandTypeString, OperandTypeString), func(p Parser, stack *CoreStack, context context.Context) {
 		op1, ok1 := stack.Pop().(string)
 		op2, ok2 := stack.Pop().(string)
 
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+type Backoff struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+	jitter       float64
+	attempt      int
+	random       *rand.Rand
+}
+
+// NewBackoff creates an exponential backoff starting at initialDelay and growing by multiplier up to maxDelay.
+// jitter is the fraction (0-1) of every delay that is randomly taken off, so instances don't retry in lockstep.
+func NewBackoff(initialDelay, maxDelay time.Duration, multiplier, jitter float64) *Backoff {
+	return &Backoff{
+		initialDelay: initialDelay,
+		maxDelay:     maxDelay,
+		multiplier:   multiplier,
+		jitter:       jitter,
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (b *Backoff) Next() time.Duration {
+	delay := float64(b.initialDelay) * math.Pow(b.multiplier, float64(b.attempt))
+	if delay >= float64(b.maxDelay) {
+		delay = float64(b.maxDelay)
+	} else {
+		b.attempt++
+	}
+
+	delay -= delay * b.jitter * b.random.Float64()
+	return time.Duration(delay)
+}
+
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// RunPeriodicTaskWithBackoff runs action every period as long as it succeeds, and retries it with the given backoff
+// while it fails. succeeded tells whether the run preceding the task (if any) was successful.
+func RunPeriodicTaskWithBackoff(action func() bool, period time.Duration, backoff *Backoff, succeeded bool, quit <-chan struct{}) {
+	go func() {
+		for {
+			var delay time.Duration
+			if succeeded {
+				backoff.Reset()
+				delay = period
+			} else {
+				delay = backoff.Next()
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+				succeeded = action()
+			case <-quit:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
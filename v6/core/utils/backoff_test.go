@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffGrowsUntilMaxDelay(t *testing.T) {
+	backoff := NewBackoff(time.Second, 5*time.Second, 2, 0)
+
+	assert.Equal(t, time.Second, backoff.Next())
+	assert.Equal(t, 2*time.Second, backoff.Next())
+	assert.Equal(t, 4*time.Second, backoff.Next())
+	assert.Equal(t, 5*time.Second, backoff.Next())
+	assert.Equal(t, 5*time.Second, backoff.Next())
+
+	backoff.Reset()
+	assert.Equal(t, time.Second, backoff.Next())
+}
+
+func TestBackoffJitterStaysWithinBounds(t *testing.T) {
+	backoff := NewBackoff(time.Second, time.Second, 2, 0.5)
+
+	for i := 0; i < 100; i++ {
+		delay := backoff.Next()
+		assert.True(t, delay > 500*time.Millisecond)
+		assert.True(t, delay <= time.Second)
+	}
+}
+
+func TestRunPeriodicTaskWithBackoffRetriesQuicklyAfterFailure(t *testing.T) {
+	var calls int32
+	quit := make(chan struct{})
+	defer close(quit)
+
+	succeeded := make(chan struct{})
+	RunPeriodicTaskWithBackoff(func() bool {
+		if atomic.AddInt32(&calls, 1) == 3 {
+			close(succeeded)
+			return true
+		}
+		return false
+	}, time.Hour, NewBackoff(time.Millisecond, 10*time.Millisecond, 2, 0), false, quit)
+
+	select {
+	case <-succeeded:
+	case <-time.After(time.Second):
+		assert.Fail(t, "failed task was not retried")
+	}
+
+	// after the success the task waits for the full period
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
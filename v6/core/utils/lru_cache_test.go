@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCacheWillEvictLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	cache.Get("a")
+	cache.Add("c", 3)
+
+	_, ok := cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestLRUCacheWillReplaceExistingValue(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Add("a", 1)
+	cache.Add("a", 2)
+
+	value, _ := cache.Get("a")
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestLRUCacheWillClear(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Add("a", 1)
+	cache.Clear()
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	gocontext "context"
 	"fmt"
+	"net"
+	"strconv"
 	"sync"
 	"time"
 
@@ -9,7 +12,6 @@ import (
 	"github.com/rollout/rox-go/v6/core"
 	"github.com/rollout/rox-go/v6/core/consts"
 	"github.com/rollout/rox-go/v6/core/context"
-	"github.com/rollout/rox-go/v6/core/logging"
 	"github.com/rollout/rox-go/v6/core/model"
 	"github.com/rollout/rox-go/v6/core/properties"
 )
@@ -43,7 +45,7 @@ func (r *Rox) Shutdown() <-chan error {
 		r.setupShutdownMutex.Lock()
 		defer r.setupShutdownMutex.Unlock()
 		if r.state != Set && r.state != Corrupted {
-			logging.GetLogger().Warn("rox can only be shutdown when it is already in Set or Corrupted state", nil)
+			r.core.Logger().Warn("rox can only be shutdown when it is already in Set or Corrupted state", nil)
 			err <- fmt.Errorf("rox can only be shutdown when it is already in Set or Corrupted state")
 		} else {
 			reset(r)
@@ -53,17 +55,43 @@ func (r *Rox) Shutdown() <-chan error {
 	return err
 }
 
+// ShutdownContext is like Shutdown, but gives up waiting once ctx is done
+func (r *Rox) ShutdownContext(ctx gocontext.Context) error {
+	select {
+	case err := <-r.Shutdown():
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (r *Rox) Setup(apiKey string, roxOptions model.RoxOptions) <-chan error {
+	return r.setup(gocontext.Background(), apiKey, roxOptions)
+}
+
+// SetupContext is like Setup, but returns once setup completes or ctx is done, whichever happens first.
+// The initial configuration fetch is cancelled with ctx, setup then completes in the background
+// with the embedded or cached configuration and keeps fetching periodically.
+func (r *Rox) SetupContext(ctx gocontext.Context, apiKey string, roxOptions model.RoxOptions) error {
+	select {
+	case err := <-r.setup(ctx, apiKey, roxOptions):
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Rox) setup(ctx gocontext.Context, apiKey string, roxOptions model.RoxOptions) <-chan error {
 	r.setupShutdownMutex.Lock()
 	defer r.setupShutdownMutex.Unlock()
 	defer func() {
-		if r := recover(); r != nil {
-			logging.GetLogger().Error("Failed in Rox.Setup", r)
+		if pErr := recover(); pErr != nil {
+			r.core.Logger().Error("Failed in Rox.Setup", pErr)
 		}
 	}()
 
 	if r.state != Idle && r.state != Corrupted {
-		logging.GetLogger().Warn("rox has already been initialised, skipping setup", nil)
+		r.core.Logger().Warn("rox has already been initialised, skipping setup", nil)
 		err := make(chan error, 1)
 		err <- fmt.Errorf("rox has already been initialised, skipping setup")
 		return err
@@ -105,14 +133,14 @@ func (r *Rox) Setup(apiKey string, roxOptions model.RoxOptions) <-chan error {
 
 		defer func() {
 			if pErr := recover(); pErr != nil {
-				logging.GetLogger().Error("Failed in Rox.Setup", pErr)
+				r.core.Logger().Error("Failed in Rox.Setup", pErr)
 				r.state = Corrupted
 				err <- fmt.Errorf(pErr.(string))
 			} else {
 				err <- nil
 			}
 		}()
-		<-r.core.Setup(sdkSettings, serverProperties, roxOptions)
+		<-r.core.SetupContext(ctx, sdkSettings, serverProperties, roxOptions)
 		r.state = Set
 	}()
 	return err
@@ -137,8 +165,8 @@ func (r *Rox) Fetch() <-chan struct{} {
 		defer close(done)
 
 		defer func() {
-			if r := recover(); r != nil {
-				logging.GetLogger().Error("Failed in Rox.Fetch", r)
+			if pErr := recover(); pErr != nil {
+				r.core.Logger().Error("Failed in Rox.Fetch", pErr)
 			}
 		}()
 
@@ -148,6 +176,56 @@ func (r *Rox) Fetch() <-chan struct{} {
 	return done
 }
 
+// FetchContext fetches and applies the configuration, cancelling the request once ctx is done
+func (r *Rox) FetchContext(ctx gocontext.Context) (status model.FetcherStatus, err error) {
+	defer func() {
+		if pErr := recover(); pErr != nil {
+			r.core.Logger().Error("Failed in Rox.FetchContext", pErr)
+			status, err = model.FetcherStatusErrorFetchedFailed, fmt.Errorf("%v", pErr)
+		}
+	}()
+
+	return r.core.FetchContext(ctx)
+}
+
+// Explain evaluates the flag named flagName for ctx, without sending an impression, and returns a trace
+// of the operators, property lookups and target groups its value was computed from
+func (r *Rox) Explain(flagName string, ctx context.Context) (*model.FlagExplanation, error) {
+	return r.core.Explain(flagName, ctx)
+}
+
+// OnFlagChanged calls handler whenever a new configuration or global context changes the value
+// of the flag or RoxString named flagName when it is evaluated with ctx
+func (r *Rox) OnFlagChanged(flagName string, ctx context.Context, handler func(oldValue, newValue string)) {
+	r.core.OnFlagChanged(flagName, ctx, handler)
+}
+
+// OnRoxIntChanged is like OnFlagChanged for a RoxInt
+func (r *Rox) OnRoxIntChanged(flagName string, ctx context.Context, handler func(oldValue, newValue int)) {
+	r.core.OnFlagChanged(flagName, ctx, func(oldValue, newValue string) {
+		oldInt, oldErr := strconv.Atoi(oldValue)
+		newInt, newErr := strconv.Atoi(newValue)
+		if oldErr != nil || newErr != nil {
+			r.core.Logger().Warn(fmt.Sprintf("Flag %s is not a RoxInt", flagName), nil)
+			return
+		}
+		handler(oldInt, newInt)
+	})
+}
+
+// OnRoxDoubleChanged is like OnFlagChanged for a RoxDouble
+func (r *Rox) OnRoxDoubleChanged(flagName string, ctx context.Context, handler func(oldValue, newValue float64)) {
+	r.core.OnFlagChanged(flagName, ctx, func(oldValue, newValue string) {
+		oldDouble, oldErr := strconv.ParseFloat(oldValue, 64)
+		newDouble, newErr := strconv.ParseFloat(newValue, 64)
+		if oldErr != nil || newErr != nil {
+			r.core.Logger().Warn(fmt.Sprintf("Flag %s is not a RoxDouble", flagName), nil)
+			return
+		}
+		handler(oldDouble, newDouble)
+	})
+}
+
 func (r *Rox) SetCustomStringProperty(name string, value string) {
 	r.core.AddCustomProperty(properties.NewStringProperty(name, value))
 }
@@ -196,6 +274,22 @@ func (r *Rox) SetCustomComputedSemverProperty(name string, value properties.Cust
 	r.core.AddCustomProperty(properties.NewComputedSemverProperty(name, value))
 }
 
+func (r *Rox) SetCustomStringListProperty(name string, value []string) {
+	r.core.AddCustomProperty(properties.NewStringListProperty(name, value))
+}
+
+func (r *Rox) SetCustomComputedStringListProperty(name string, value properties.CustomStringListPropertyGenerator) {
+	r.core.AddCustomProperty(properties.NewComputedStringListProperty(name, value))
+}
+
+func (r *Rox) SetCustomIPProperty(name string, value net.IP) {
+	r.core.AddCustomProperty(properties.NewIPProperty(name, value))
+}
+
+func (r *Rox) SetCustomComputedIPProperty(name string, value properties.CustomIPPropertyGenerator) {
+	r.core.AddCustomProperty(properties.NewComputedIPProperty(name, value))
+}
+
 func (r *Rox) DynamicAPI() model.DynamicAPI {
 	return r.core.DynamicAPI(&ServerEntitiesProvider{})
 }
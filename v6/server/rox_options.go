@@ -1,6 +1,7 @@
 package server
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/rollout/rox-go/v6/core/logging"
@@ -22,6 +23,22 @@ type RoxOptionsBuilder struct {
 	DynamicPropertyRuleHandler   model.DynamicPropertyRuleHandler
 	NetworkConfigurationsOptions model.NetworkConfigurationsOptions
 	DisableSignatureVerification bool
+	// EmbeddedConfiguration is a signed configuration JSON applied before the first network fetch
+	EmbeddedConfiguration string
+	ConfigurationCache    model.ConfigurationCache
+	// ConfigurationFilePath makes the SDK read its configuration from a local file instead of the network
+	ConfigurationFilePath string
+	FetchRetryPolicy      *model.FetchRetryPolicy
+	// HTTPClient is used for all the SDK network traffic, http.DefaultClient is used when it is not set
+	HTTPClient *http.Client
+	// RequestInterceptors and ResponseInterceptors are invoked in order for every SDK request, including the push updates stream
+	RequestInterceptors  []model.RequestInterceptor
+	ResponseInterceptors []model.ResponseInterceptor
+	// EvaluationErrorHandler is called with every flag condition that fails to evaluate, such as an operator given an operand of the wrong type
+	EvaluationErrorHandler model.EvaluationErrorHandler
+	// StrictEvaluation makes a failing target group or flag dependency fail the flag condition evaluating it,
+	// so the flag falls back to its default value, instead of counting as false
+	StrictEvaluation bool
 }
 
 type roxOptions struct {
@@ -38,6 +55,16 @@ type roxOptions struct {
 	dynamicPropertyRuleHandler   model.DynamicPropertyRuleHandler
 	networkConfigurationsOptions model.NetworkConfigurationsOptions
 	disableSignatureVerification bool
+	embeddedConfiguration        string
+	configurationCache           model.ConfigurationCache
+	configurationFilePath        string
+	fetchRetryPolicy             *model.FetchRetryPolicy
+	httpClient                   *http.Client
+	logger                       logging.Logger
+	requestInterceptors          []model.RequestInterceptor
+	responseInterceptors         []model.ResponseInterceptor
+	evaluationErrorHandler       model.EvaluationErrorHandler
+	strictEvaluation             bool
 }
 
 func NewRoxOptions(builder RoxOptionsBuilder) model.RoxOptions {
@@ -60,11 +87,12 @@ func NewRoxOptions(builder RoxOptionsBuilder) model.RoxOptions {
 		fetchInterval = 60 * time.Second
 	}
 
-	if builder.Logger != nil {
-		logging.SetLogger(builder.Logger)
-	} else {
-		logging.SetLogger(NewServerLogger())
+	logger := builder.Logger
+	if logger == nil {
+		logger = NewServerLogger()
 	}
+	// flags and repositories created outside of an instance log to the global logger
+	logging.SetLogger(logger)
 
 	if !builder.DisableAnalyticsReporting && builder.AnalyticsReportInterval == 0 {
 		builder.AnalyticsReportInterval = 1 * time.Minute
@@ -94,9 +122,44 @@ func NewRoxOptions(builder RoxOptionsBuilder) model.RoxOptions {
 		dynamicPropertyRuleHandler:   dynamicPropertyRuleHandler,
 		networkConfigurationsOptions: builder.NetworkConfigurationsOptions,
 		disableSignatureVerification: builder.DisableSignatureVerification,
+		embeddedConfiguration:        builder.EmbeddedConfiguration,
+		configurationCache:           builder.ConfigurationCache,
+		configurationFilePath:        builder.ConfigurationFilePath,
+		fetchRetryPolicy:             newFetchRetryPolicy(builder.FetchRetryPolicy),
+		httpClient:                   builder.HTTPClient,
+		logger:                       logger,
+		requestInterceptors:          builder.RequestInterceptors,
+		responseInterceptors:         builder.ResponseInterceptors,
+		evaluationErrorHandler:       builder.EvaluationErrorHandler,
+		strictEvaluation:             builder.StrictEvaluation,
 	}
 }
 
+func newFetchRetryPolicy(policy *model.FetchRetryPolicy) *model.FetchRetryPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	result := model.DefaultFetchRetryPolicy()
+	if policy.InitialDelay > 0 {
+		result.InitialDelay = policy.InitialDelay
+	}
+	if policy.MaxDelay > 0 {
+		result.MaxDelay = policy.MaxDelay
+	}
+	if result.MaxDelay < result.InitialDelay {
+		result.MaxDelay = result.InitialDelay
+	}
+	if policy.Multiplier >= 1 {
+		result.Multiplier = policy.Multiplier
+	}
+	if policy.Jitter != nil && *policy.Jitter >= 0 && *policy.Jitter <= 1 {
+		jitter := *policy.Jitter
+		result.Jitter = &jitter
+	}
+	return result
+}
+
 func (ro *roxOptions) DevModeKey() string {
 	return ro.devModeKey
 }
@@ -137,6 +200,46 @@ func (ro *roxOptions) IsSignatureVerificationDisabled() bool {
 	return ro.disableSignatureVerification
 }
 
+func (ro *roxOptions) EmbeddedConfiguration() string {
+	return ro.embeddedConfiguration
+}
+
+func (ro *roxOptions) ConfigurationCache() model.ConfigurationCache {
+	return ro.configurationCache
+}
+
+func (ro *roxOptions) ConfigurationFilePath() string {
+	return ro.configurationFilePath
+}
+
+func (ro *roxOptions) FetchRetryPolicy() *model.FetchRetryPolicy {
+	return ro.fetchRetryPolicy
+}
+
+func (ro *roxOptions) HTTPClient() *http.Client {
+	return ro.httpClient
+}
+
+func (ro *roxOptions) RequestInterceptors() []model.RequestInterceptor {
+	return ro.requestInterceptors
+}
+
+func (ro *roxOptions) ResponseInterceptors() []model.ResponseInterceptor {
+	return ro.responseInterceptors
+}
+
+func (ro *roxOptions) Logger() logging.Logger {
+	return ro.logger
+}
+
+func (ro *roxOptions) EvaluationErrorHandler() model.EvaluationErrorHandler {
+	return ro.evaluationErrorHandler
+}
+
+func (ro *roxOptions) IsStrictEvaluationEnabled() bool {
+	return ro.strictEvaluation
+}
+
 func (ro *roxOptions) AnalyticsReportInterval() time.Duration {
 	return ro.analyticsReportInterval
 }